@@ -8,20 +8,21 @@ terraform {
 }
 
 # Configure terraform to use a local provider build
+# The provider obtains the access token with these credentials, so it is not stored in the resource state
 provider "guardium-data-protection" {
-  host = var.host
-  port = var.port
-}
-
-data "guardium-data-protection_authentication" "auth" {
+  host          = var.host
+  port          = var.port
   client_id     = var.client_id
   client_secret = var.client_secret
   username      = var.username
   password      = var.password
+
+  tls {
+    ca_file = var.ca_file
+  }
 }
 
 resource "guardium-data-protection_aws_secrets_manager" "example" {
-  access_token        = data.guardium-data-protection_authentication.auth.access_token
   name                = var.aws_config_name
   auth_type           = var.auth_type
   access_key_id       = var.access_key_id
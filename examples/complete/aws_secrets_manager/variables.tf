@@ -8,6 +8,12 @@ variable "port" {
   type        = string
 }
 
+variable "ca_file" {
+  description = "Path to the PEM encoded certificate authority that signed the Guardium Data Protection certificate"
+  type        = string
+  default     = null
+}
+
 variable "client_id" {
   description = "The client ID for authentication"
   type        = string
@@ -0,0 +1,6 @@
+# Detect the Guardium version of the appliance
+data "guardium-data-protection_appliance_version" "current" {}
+
+output "guardium_version" {
+  value = data.guardium-data-protection_appliance_version.current.version
+}
@@ -6,25 +6,19 @@ terraform {
   }
 }
 
-provider "guardium-data-protection" {
-  host = "cm-rr-guardium.dev.fyre.ibm.com"
-  port = "8443"
-}
-#
-# data "guardium-data-protection_authentication" "access_token" {
-#   	client_secret = ""
-#   	username = "admin"
-#   	password = ""
-# }
-
-
+# The host, port and certificate authority default to the GUARDIUM_HOST, GUARDIUM_PORT and GUARDIUM_CA_FILE
+# environment variables, which `go run test/main.go` prints for the mock server
+provider "guardium-data-protection" {}
 
-
-data "guardium-data-protection_docdb_parameter_group" "access_token" {
-  cluster_identifier = "guardium-docdb"
-  region             = "us-east-1"
+# Credentials accepted by the mock server by default
+data "guardium-data-protection_authentication" "access_token" {
+  client_id     = "client1"
+  client_secret = "mock-secret"
+  username      = "admin"
+  password      = "mock-password"
 }
 
 output "example" {
-  value = data.guardium-data-protection_docdb_parameter_group.access_token.parameter_group
-}
\ No newline at end of file
+  value     = data.guardium-data-protection_authentication.access_token.access_token
+  sensitive = true
+}
@@ -0,0 +1,17 @@
+# Check that the appliance can be used before managing it
+data "guardium-data-protection_health" "current" {
+  lifecycle {
+    postcondition {
+      condition     = self.error == ""
+      error_message = "Guardium Data Protection is not usable: ${self.error}"
+    }
+  }
+}
+
+output "guardium_role" {
+  value = data.guardium-data-protection_health.current.role
+}
+
+output "guardium_certificate_expiry" {
+  value = data.guardium-data-protection_health.current.tls.not_after
+}
@@ -0,0 +1,23 @@
+# Read the vulnerability assessment configuration of a datasource
+data "guardium-data-protection_rest_call" "va_config" {
+  command = "va/config"
+  parameters = {
+    datasourceName = "example-datasource"
+  }
+}
+
+output "va_schedule" {
+  value = data.guardium-data-protection_rest_call.va_config.result.schedule
+}
+
+# List every registered datasource, reading 100 at a time
+data "guardium-data-protection_rest_call" "datasources" {
+  command = "datasource"
+  pagination = {
+    page_size = 100
+  }
+}
+
+output "datasource_names" {
+  value = [for datasource in data.guardium-data-protection_rest_call.datasources.result : datasource.datasourceName]
+}
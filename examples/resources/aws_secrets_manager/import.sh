@@ -0,0 +1,2 @@
+# AWS Secrets Manager configurations are imported by name
+terraform import guardium-data-protection_aws_secrets_manager.example aws-prod
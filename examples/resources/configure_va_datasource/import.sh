@@ -0,0 +1,2 @@
+# The appliance does not report assessment configurations, they are imported with their settings
+terraform import guardium-data-protection_configure_va_datasource.example '{"datasource_name":"db2-prod","assessment_schedule":"weekly","assessment_day":"Monday","assessment_time":"23:00"}'
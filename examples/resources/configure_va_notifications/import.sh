@@ -0,0 +1,2 @@
+# The appliance does not report notification configurations, they are imported with their settings
+terraform import guardium-data-protection_configure_va_notifications.example '{"datasource_name":"db2-prod","notification_type":"email","notification_emails":["dba@example.com"],"notification_severity":"high"}'
@@ -0,0 +1,2 @@
+# The appliance does not report datasource registrations, they are imported with their payload
+terraform import guardium-data-protection_register_va_datasource.example "$(cat datasource.json)"
@@ -0,0 +1,29 @@
+# Manage a Guardium group the provider has no dedicated resource for
+resource "guardium-data-protection_rest_command" "group" {
+  create = {
+    command = "group"
+    body = jsonencode({
+      desc           = "terraform-managed-servers"
+      type           = "Server IP"
+      appid          = "Public"
+      category       = "terraform"
+      classification = "servers"
+    })
+  }
+
+  update = {
+    method  = "PUT"
+    command = "group"
+    body = jsonencode({
+      desc     = "terraform-managed-servers"
+      category = "terraform"
+    })
+  }
+
+  delete = {
+    command = "group"
+    body = jsonencode({
+      desc = "terraform-managed-servers"
+    })
+  }
+}
@@ -0,0 +1,41 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import "context"
+
+// API is the set of Guardium Data Protection operations used by the provider. SecureClient and InsecureClient
+// run them against an appliance, gdptest.Fake keeps their effects in memory for tests.
+type API interface {
+	Executor
+
+	ImportProfilesFromFile(ctx context.Context, accessToken, pathToFile string, updateMode bool) error
+	GenerateAccessToken(ctx context.Context, credentials Credentials) (string, error)
+	BulkInstallConnector(ctx context.Context, accessToken, udcName, gdpMuHost string) error
+	CreateAWSSecretsManager(ctx context.Context, accessToken string, config *AWSSecretsManagerConfig) error
+	GetAWSSecretsManager(ctx context.Context, accessToken string, name string) (*AWSSecretsManagerConfig, error)
+	GetExistingAWSSecretsManagerNames(ctx context.Context, accessToken string) ([]string, error)
+	UpdateAWSSecretsManager(ctx context.Context, accessToken string, config *AWSSecretsManagerConfig) error
+	DeleteAWSSecretsManager(ctx context.Context, accessToken string, name string) error
+	RegisterVADataSource(ctx context.Context, accessToken string, payload []byte) error
+	ConfigureVADataSource(ctx context.Context, accessToken string, payload []byte) error
+	ConfigureVANotifications(ctx context.Context, accessToken string, payload []byte) error
+	ApplianceVersion(ctx context.Context, accessToken string) (ApplianceVersion, error)
+	RequireCapability(ctx context.Context, accessToken string, capability Capability) error
+	ValidateTargetUnit(ctx context.Context, accessToken, unit string) error
+	Health(ctx context.Context, accessToken string) (*Health, error)
+
+	// Host returns the appliance host name
+	Host() string
+	// HasCredentials reports whether access tokens can be obtained with the provider credentials
+	HasCredentials() bool
+	// WithCAPath returns an API verifying the appliance certificate against the PEM encoded certificate
+	// authority bundle at caPath, or the provider level TLS settings when it is empty
+	WithCAPath(caPath string) (API, error)
+}
+
+var (
+	_ API = &InsecureClient{}
+	_ API = &SecureClient{}
+)
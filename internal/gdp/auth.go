@@ -0,0 +1,310 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	oauthTokenPath = "/oauth/token"
+
+	// tokenRefreshMargin is how long before expiry a provider access token is refreshed. Short lived tokens
+	// are refreshed halfway through their lifetime instead.
+	tokenRefreshMargin = 60 * time.Second
+)
+
+// OAuth grant types supported by the Guardium Data Protection token endpoint
+const (
+	GrantTypePassword          = "password"
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeRefreshToken      = "refresh_token"
+)
+
+// GrantTypes lists the supported OAuth grant types
+var GrantTypes = []string{GrantTypePassword, GrantTypeClientCredentials, GrantTypeRefreshToken}
+
+// How the OAuth client authenticates to the token endpoint
+const (
+	// ClientAuthBody sends client_id and client_secret in the form encoded request body
+	ClientAuthBody = "body"
+	// ClientAuthBasic sends client_id and client_secret as HTTP basic authentication
+	ClientAuthBasic = "basic"
+)
+
+// ClientAuthMethods lists the supported client authentication methods
+var ClientAuthMethods = []string{ClientAuthBody, ClientAuthBasic}
+
+// Credentials are the OAuth client and user credentials the provider uses to obtain access tokens
+type Credentials struct {
+	// GrantType is one of GrantTypes, defaults to GrantTypePassword
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	// Username and Password are used by the password grant
+	Username string
+	Password string
+	// RefreshToken is used by the refresh_token grant
+	RefreshToken string
+	// ClientAuth is one of ClientAuthMethods, defaults to ClientAuthBody
+	ClientAuth string
+
+	// PasswordFile and ClientSecretFile are files holding Password and ClientSecret when those are not set
+	PasswordFile     string
+	ClientSecretFile string
+	// Process is a command printing the client_id, client_secret, username and password as a JSON object,
+	// used for those not set otherwise
+	Process string
+}
+
+func (c Credentials) grantType() string {
+	if c.GrantType == "" {
+		return GrantTypePassword
+	}
+	return c.GrantType
+}
+
+// RequiredFields returns the names of the OAuth parameters the grant type needs
+func (c Credentials) RequiredFields() []string {
+	switch c.grantType() {
+	case GrantTypeClientCredentials:
+		return []string{"client_id", "client_secret"}
+	case GrantTypeRefreshToken:
+		return []string{"client_id", "refresh_token"}
+	default:
+		return []string{"client_id", "client_secret", "username", "password"}
+	}
+}
+
+// Validate reports an unsupported grant type or client authentication method and missing parameters
+func (c Credentials) Validate() error {
+	if !slices.Contains(GrantTypes, c.grantType()) {
+		return fmt.Errorf("unsupported grant type %q, expected one of %s", c.GrantType, strings.Join(GrantTypes, ", "))
+	}
+	if c.ClientAuth != "" && !slices.Contains(ClientAuthMethods, c.ClientAuth) {
+		return fmt.Errorf("unsupported client authentication %q, expected one of %s", c.ClientAuth, strings.Join(ClientAuthMethods, ", "))
+	}
+
+	values := map[string]string{
+		"client_id":     c.ClientID,
+		"client_secret": c.ClientSecret,
+		"username":      c.Username,
+		"password":      c.Password,
+		"refresh_token": c.RefreshToken,
+	}
+	// The credential process may provide any value, it is checked once the process has run
+	if c.Process != "" {
+		return nil
+	}
+	if c.PasswordFile != "" {
+		values["password"] = c.PasswordFile
+	}
+	if c.ClientSecretFile != "" {
+		values["client_secret"] = c.ClientSecretFile
+	}
+
+	var missing []string
+	for _, name := range c.RequiredFields() {
+		if values[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the %s grant requires %s, missing %s", c.grantType(), strings.Join(c.RequiredFields(), ", "), strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// errNoAccessToken is returned when a call has neither an explicit access token nor provider credentials
+var errNoAccessToken = errors.New("no access token available: set access_token or configure credentials on the provider")
+
+// ConfigureCredentials sets the credentials used to obtain an access token for calls made without one
+func (c *Client) ConfigureCredentials(credentials Credentials) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.credentialsMu.Lock()
+	defer c.credentialsMu.Unlock()
+
+	c.credentials = &credentials
+	c.token = ""
+	c.user = ""
+	c.refreshToken = ""
+	c.tokenRefreshAt = time.Time{}
+}
+
+// HasCredentials reports whether provider credentials are configured
+func (c *Client) HasCredentials() bool {
+	c.credentialsMu.RLock()
+	defer c.credentialsMu.RUnlock()
+
+	return c.credentials != nil
+}
+
+// providerToken returns the access token obtained with the provider credentials. A token is requested on
+// first use and again when the current one is about to expire; concurrent callers wait for that request.
+func (c *Client) providerToken(ctx context.Context, httpClient *http.Client) (string, error) {
+	if err := c.tokenMu.LockContext(ctx); err != nil {
+		return "", err
+	}
+	defer c.tokenMu.Unlock()
+
+	c.credentialsMu.RLock()
+	credentials := c.credentials
+	c.credentialsMu.RUnlock()
+	if credentials == nil {
+		return "", errNoAccessToken
+	}
+	if c.token != "" && (c.tokenRefreshAt.IsZero() || time.Now().Before(c.tokenRefreshAt)) {
+		return c.token, nil
+	}
+
+	otr, err := c.obtainToken(ctx, httpClient, *credentials)
+	if err != nil {
+		return "", err
+	}
+
+	c.credentialsMu.Lock()
+	c.user = otr.User
+	c.credentialsMu.Unlock()
+
+	c.token = otr.AccessToken
+	c.tokenRefreshAt = time.Time{}
+	if otr.ExpiresIn > 0 {
+		lifetime := time.Duration(otr.ExpiresIn) * time.Second
+		c.tokenRefreshAt = time.Now().Add(lifetime - min(tokenRefreshMargin, lifetime/2))
+	}
+	// Keep the previous refresh token when the appliance does not rotate it
+	if otr.RefreshToken != "" {
+		c.refreshToken = otr.RefreshToken
+	}
+
+	return c.token, nil
+}
+
+// tokenUser returns the user the provider access token was issued to, empty before one is obtained
+func (c *Client) tokenUser() string {
+	c.credentialsMu.RLock()
+	defer c.credentialsMu.RUnlock()
+
+	return c.user
+}
+
+// obtainToken uses the refresh token when one was issued, falling back to the configured grant when the
+// appliance rejects it. Callers must hold tokenMu.
+func (c *Client) obtainToken(ctx context.Context, httpClient *http.Client, credentials Credentials) (*OauthTokenResponse, error) {
+	if c.refreshToken != "" {
+		otr, err := c.refreshAccessToken(ctx, httpClient, credentials, c.refreshToken)
+		if err == nil {
+			return otr, nil
+		}
+		tflog.Debug(ctx, "refresh token rejected, requesting a new access token with the provider credentials")
+		c.refreshToken = ""
+	}
+
+	return c.generateAccessToken(ctx, httpClient, credentials)
+}
+
+// invalidateToken discards the provider access token after the appliance rejected it. A token that was
+// already replaced by a concurrent caller is left alone, as is the token when ctx is done first.
+func (c *Client) invalidateToken(ctx context.Context, token string) {
+	if c.tokenMu.LockContext(ctx) != nil {
+		return
+	}
+	defer c.tokenMu.Unlock()
+
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+// setBearer sets the Authorization header when an explicit access token is given. Requests without one
+// are authorized by authTransport with the provider token.
+func setBearer(req *http.Request, accessToken string) {
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+}
+
+type withoutAuthorizationKey struct{}
+
+// withoutAuthorization marks requests made with ctx to be sent without the provider access token
+func withoutAuthorization(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutAuthorizationKey{}, true)
+}
+
+// authTransport authorizes requests that carry no Authorization header with the provider access token,
+// unless their context is marked with withoutAuthorization
+type authTransport struct {
+	next   http.RoundTripper
+	client *Client
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	skip, _ := req.Context().Value(withoutAuthorizationKey{}).(bool)
+	if skip || req.Header.Get("Authorization") != "" || req.URL.Path == oauthTokenPath {
+		return t.next.RoundTrip(req)
+	}
+
+	// The token is requested over the same transport so it shares the TLS settings of the call
+	tokenClient := &http.Client{Transport: t.next}
+	token, err := t.client.providerToken(req.Context(), tokenClient)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(authorize(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// A request whose body cannot be replayed is returned as is
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	// The rejection is buffered and closed first, releasing its slot of the appliance budget for the token request
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// The token was revoked or expired early: get a new one and try once more
+	tflog.Debug(req.Context(), "access token rejected, requesting a new one")
+	t.client.invalidateToken(req.Context(), token)
+	token, err = t.client.providerToken(req.Context(), tokenClient)
+	if err != nil {
+		return resp, nil
+	}
+
+	retry := authorize(req, token)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+
+	return t.next.RoundTrip(retry)
+}
+
+// authorize returns a copy of req carrying the given bearer token
+func authorize(req *http.Request, token string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func (t *authTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
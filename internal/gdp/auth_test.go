@@ -0,0 +1,226 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProviderCredentials(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		credentials           *Credentials
+		accessToken           string
+		expectedAuthorization string
+		expectedTokenRequests int32
+		expectError           bool
+	}{
+		{
+			name:                  "Provider token injected",
+			credentials:           &Credentials{ClientID: "client1", ClientSecret: "secret", Username: "user", Password: "pass"},
+			expectedAuthorization: "Bearer provider-token",
+			expectedTokenRequests: 1,
+		},
+		{
+			name:                  "Explicit access token wins",
+			credentials:           &Credentials{ClientID: "client1", ClientSecret: "secret", Username: "user", Password: "pass"},
+			accessToken:           "resource-token",
+			expectedAuthorization: "Bearer resource-token",
+			expectedTokenRequests: 0,
+		},
+		{
+			name:                  "Explicit access token without provider credentials",
+			accessToken:           "resource-token",
+			expectedAuthorization: "Bearer resource-token",
+		},
+		{
+			name:        "No access token and no provider credentials",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var tokenRequests atomic.Int32
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == oauthTokenPath {
+					tokenRequests.Add(1)
+					_, _ = w.Write([]byte(`{"access_token":"provider-token"}`))
+					return
+				}
+
+				if got := r.Header.Get("Authorization"); got != tc.expectedAuthorization {
+					t.Errorf("Expected Authorization %q, got %q", tc.expectedAuthorization, got)
+				}
+				_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+			}))
+			defer server.Close()
+
+			serverURL := strings.TrimPrefix(server.URL, "https://")
+			urlSplit := strings.Split(serverURL, ":")
+			client := NewClient(urlSplit[0], urlSplit[1])
+			if tc.credentials != nil {
+				client.ConfigureCredentials(*tc.credentials)
+			}
+
+			// Several calls share a single provider token
+			for i := 0; i < 3; i++ {
+				err := client.NewInsecureClient().BulkInstallConnector(context.Background(), tc.accessToken, "connector-profile", "host1.example.com")
+				if tc.expectError {
+					if !errors.Is(err, errNoAccessToken) {
+						t.Fatalf("Expected errNoAccessToken but got: %v", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Expected no error but got: %v", err)
+				}
+			}
+
+			if got := tokenRequests.Load(); got != tc.expectedTokenRequests {
+				t.Errorf("Expected %d token requests, got %d", tc.expectedTokenRequests, got)
+			}
+		})
+	}
+}
+
+func TestProviderTokenRefresh(t *testing.T) {
+	var tokenRequests atomic.Int32
+	var grantTypes []string
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == oauthTokenPath {
+			n := tokenRequests.Add(1)
+			grantTypes = append(grantTypes, r.FormValue("grant_type"))
+			_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","refresh_token":"refresh-%d","expires_in":3600}`, n, n)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.ConfigureCredentials(Credentials{ClientID: "client1", ClientSecret: "secret", Username: "user", Password: "pass"})
+
+	ctx := context.Background()
+	httpClient := server.Client()
+
+	token, err := client.providerToken(ctx, httpClient)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if token != "token-1" {
+		t.Errorf("Expected token-1, got %s", token)
+	}
+	if until := time.Until(client.tokenRefreshAt); until < 58*time.Minute || until > time.Hour {
+		t.Errorf("Expected refresh a minute before expiry, got %s from now", until)
+	}
+
+	// A token that is still valid is reused
+	if token, _ = client.providerToken(ctx, httpClient); token != "token-1" {
+		t.Errorf("Expected cached token-1, got %s", token)
+	}
+
+	// A token close to expiry is refreshed with the refresh token
+	client.tokenRefreshAt = time.Now().Add(-time.Second)
+	if token, _ = client.providerToken(ctx, httpClient); token != "token-2" {
+		t.Errorf("Expected refreshed token-2, got %s", token)
+	}
+
+	expectedGrants := []string{"password", "refresh_token"}
+	if fmt.Sprint(grantTypes) != fmt.Sprint(expectedGrants) {
+		t.Errorf("Expected grant types %v, got %v", expectedGrants, grantTypes)
+	}
+}
+
+func TestProviderTokenRejected(t *testing.T) {
+	var tokenRequests atomic.Int32
+	var calls atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == oauthTokenPath {
+			n := tokenRequests.Add(1)
+			_, _ = fmt.Fprintf(w, `{"access_token":"token-%d"}`, n)
+			return
+		}
+
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "connector-profile") {
+			t.Errorf("Expected request body to be replayed, got %q", string(body))
+		}
+		// The first token is revoked by the appliance
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.ConfigureCredentials(Credentials{ClientID: "client1", ClientSecret: "secret", Username: "user", Password: "pass"})
+
+	err := client.NewInsecureClient().BulkInstallConnector(context.Background(), "", "connector-profile", "host1.example.com")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if got := tokenRequests.Load(); got != 2 {
+		t.Errorf("Expected 2 token requests, got %d", got)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected the request to be retried once, got %d calls", got)
+	}
+}
+
+func TestCredentialsDuringTokenRequest(t *testing.T) {
+	requested, unblock := make(chan struct{}), make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-unblock
+		_, _ = w.Write([]byte(`{"access_token":"token-1","user":"admin"}`))
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	client := newTestClient(server)
+	client.ConfigureCredentials(Credentials{ClientID: "client1", ClientSecret: "secret", Username: "admin", Password: "pass"})
+	go func() {
+		_, _ = client.providerToken(context.Background(), server.Client())
+	}()
+	<-requested
+
+	// The credentials are read without waiting for the token request in progress
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if !client.HasCredentials() {
+			t.Error("Expected credentials to be configured")
+		}
+		if user := client.tokenUser(); user != "" {
+			t.Errorf("Expected no user before a token is obtained, got %q", user)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the credentials to be read while a token is requested")
+	}
+}
+
+// newTestClient returns a client pointed at the given test server
+func newTestClient(server *httptest.Server) *Client {
+	urlSplit := strings.Split(strings.TrimPrefix(server.URL, "https://"), ":")
+	return NewClient(urlSplit[0], urlSplit[1])
+}
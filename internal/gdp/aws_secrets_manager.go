@@ -4,14 +4,9 @@
 package gdp
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // AWSSecretsManagerConfig represents the configuration for AWS Secrets Manager
@@ -36,86 +31,29 @@ func NewAWSSecretsManagerConfig(name, authType, accessKeyID, secretAccessKey, se
 	}
 }
 
+// awsSecretsManagerCommand is the GuardAPI command managing AWS Secrets Manager configurations
+const awsSecretsManagerCommand = "aws_secrets_manager"
+
 // CreateAWSSecretsManager creates a new AWS Secrets Manager configuration
 func (c *Client) CreateAWSSecretsManager(ctx context.Context, httpClient *http.Client, accessToken string, config *AWSSecretsManagerConfig) error {
-	// Prepare the request URL
-	url := fmt.Sprintf("%s://%s:%s/restAPI/aws_secrets_manager", c.protocol, c.Host, c.port)
-
-	// Marshal the request body
-	jsonBody, err := json.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("error marshaling request body: %w", err)
-	}
-
-	tflog.Debug(ctx, "AWS Secrets Manager create request URL: "+url)
-	tflog.Debug(ctx, "AWS Secrets Manager create request body: "+string(jsonBody))
-
-	// Create the request - use POST for creating new configurations
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send the request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error sending request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check the response status
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("error response from server: %s, status code: %d", string(body), resp.StatusCode)
-	}
-
-	tflog.Debug(ctx, "AWS Secrets Manager create response: "+string(body))
-	return nil
+	// Use POST for creating new configurations
+	_, err := c.Execute(ctx, httpClient, accessToken, Command{
+		Method: http.MethodPost,
+		Name:   awsSecretsManagerCommand,
+		Params: config,
+	})
+	return err
 }
 
 // UpdateAWSSecretsManager updates an existing AWS Secrets Manager configuration
 func (c *Client) UpdateAWSSecretsManager(ctx context.Context, httpClient *http.Client, accessToken string, config *AWSSecretsManagerConfig) error {
-	// Prepare the request URL
-	url := fmt.Sprintf("%s://%s:%s/restAPI/aws_secrets_manager", c.protocol, c.Host, c.port)
-
-	// Marshal the request body
-	jsonBody, err := json.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("error marshaling request body: %w", err)
-	}
-
-	tflog.Debug(ctx, "AWS Secrets Manager update request URL: "+url)
-	tflog.Debug(ctx, "AWS Secrets Manager update request body: "+string(jsonBody))
-
-	// Create the request - use PUT for updating existing configurations
-	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send the request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error sending request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check the response status
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("error response from server: %s, status code: %d", string(body), resp.StatusCode)
-	}
-
-	tflog.Debug(ctx, "AWS Secrets Manager update response: "+string(body))
-	return nil
+	// Use PUT for updating existing configurations
+	_, err := c.Execute(ctx, httpClient, accessToken, Command{
+		Method: http.MethodPut,
+		Name:   awsSecretsManagerCommand,
+		Params: config,
+	})
+	return err
 }
 
 // GetExistingAWSSecretsManagerNames gets the list of existing AWS Secrets Manager configuration names
@@ -135,56 +73,25 @@ func (c *Client) GetExistingAWSSecretsManagerNames(ctx context.Context, httpClie
 	return names, nil
 }
 
-// GetAllAWSSecretsManagerConfigs gets all AWS Secrets Manager configurations
-func (c *Client) GetAllAWSSecretsManagerConfigs(ctx context.Context, httpClient *http.Client, accessToken string) ([]AWSSecretsManagerConfig, error) {
-	// Prepare the request URL
-	url := fmt.Sprintf("%s://%s:%s/restAPI/aws_secrets_manager", c.protocol, c.Host, c.port)
-
-	// Create the request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-
-	// Send the request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check the response status
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error response from server: %s, status code: %d", string(body), resp.StatusCode)
-	}
+// awsSecretsManagerListItem is a configuration as listed by the appliance
+type awsSecretsManagerListItem struct {
+	ID                          int    `json:"id"`
+	Name                        string `json:"name"`
+	AccessKeyID                 string `json:"accessKeyId"`
+	SecretAccessKey             string `json:"secretAccessKey"`
+	AuthType                    string `json:"authType"`
+	RoleARN                     string `json:"roleARN"`
+	SecretKeyUsernameIdentifier string `json:"secretKeyUsernameIdentifier"`
+	SecretKeyPasswordIdentifier string `json:"secretKeyPasswordIdentifier"`
+	SecretsManager              bool   `json:"secretsManager"`
+}
 
-	// Parse the response
-	body, err := io.ReadAll(resp.Body)
+// GetAllAWSSecretsManagerConfigs gets all AWS Secrets Manager configurations. They are requested at once, as
+// appliances that do not page the list would otherwise return only part of it.
+func (c *Client) GetAllAWSSecretsManagerConfigs(ctx context.Context, httpClient *http.Client, accessToken string) ([]AWSSecretsManagerConfig, error) {
+	configs, err := executeAs[[]awsSecretsManagerListItem](ctx, c, httpClient, accessToken, Command{Name: awsSecretsManagerCommand})
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
-	}
-
-	tflog.Debug(ctx, "AWS Secrets Manager response body: "+string(body))
-
-	// Try to unmarshal as an array
-	var configs []struct {
-		ID                          int    `json:"id"`
-		Name                        string `json:"name"`
-		AccessKeyID                 string `json:"accessKeyId"`
-		SecretAccessKey             string `json:"secretAccessKey"`
-		AuthType                    string `json:"authType"`
-		RoleARN                     string `json:"roleARN"`
-		SecretKeyUsernameIdentifier string `json:"secretKeyUsernameIdentifier"`
-		SecretKeyPasswordIdentifier string `json:"secretKeyPasswordIdentifier"`
-		SecretsManager              bool   `json:"secretsManager"`
-	}
-
-	if err := json.Unmarshal(body, &configs); err != nil {
-		return nil, fmt.Errorf("error parsing response body: %w", err)
+		return nil, err
 	}
 
 	// Convert to AWSSecretsManagerConfig
@@ -225,49 +132,11 @@ func (c *Client) GetAWSSecretsManager(ctx context.Context, httpClient *http.Clie
 
 // DeleteAWSSecretsManager deletes an AWS Secrets Manager configuration by name
 func (c *Client) DeleteAWSSecretsManager(ctx context.Context, httpClient *http.Client, accessToken string, name string) error {
-	// Prepare the request URL
-	url := fmt.Sprintf("%s://%s:%s/restAPI/aws_secrets_manager", c.protocol, c.Host, c.port)
-
-	// Create the request body with the name parameter
-	type DeleteRequestBody struct {
-		Name string `json:"name"`
-	}
-	requestBody := DeleteRequestBody{
-		Name: name,
-	}
-
-	// Marshal the request body
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("error marshaling request body: %w", err)
-	}
-
-	tflog.Debug(ctx, "AWS Secrets Manager delete request URL: "+url)
-	tflog.Debug(ctx, "AWS Secrets Manager delete request body: "+string(jsonBody))
-
-	// Create the request
-	req, err := http.NewRequestWithContext(ctx, "DELETE", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send the request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error sending request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check the response status
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("error response from server: %s, status code: %d", string(body), resp.StatusCode)
-	}
-
-	tflog.Debug(ctx, "AWS Secrets Manager delete response: "+string(body))
-	return nil
+	// The name is passed in the request body
+	_, err := c.Execute(ctx, httpClient, accessToken, Command{
+		Method: http.MethodDelete,
+		Name:   awsSecretsManagerCommand,
+		Params: map[string]string{"name": name},
+	})
+	return err
 }
@@ -4,63 +4,173 @@
 package gdp
 
 import (
-	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type Client struct {
-	protocol string
-	Host     string
-	port     string
-}
-
-type SecureClient struct {
-	Client
-	CACertPath string
+	protocol       string
+	Host           string
+	port           string
+	tlsConfig      *tls.Config
+	retry          RetryConfig
+	requestTimeout time.Duration
+	proxy          func(*http.Request) (*url.URL, error)
+	headers        http.Header
+	targetUnit     string
+	fixtures       *fixtureStore
+
+	// mu guards the pooled HTTP clients, which are shared by every operation against the host
+	mu             sync.Mutex
+	httpClients    map[string]*http.Client
+	insecureClient *http.Client
+
+	// credentialsMu guards the provider credentials and the user they authenticate as, which are read
+	// without waiting for a token request in progress
+	credentialsMu sync.RWMutex
+	credentials   *Credentials
+	user          string
+
+	// tokenMu guards the access token obtained with the provider credentials. It is held while a token
+	// is requested so that concurrent operations wait for a single request instead of stampeding.
+	tokenMu        contextMutex
+	token          string
+	refreshToken   string
+	tokenRefreshAt time.Time
+
+	// versionMu guards the versions of the appliance, under the empty unit, and of the managed units commands
+	// are routed to, each detected once per client
+	versionMu   contextMutex
+	versions    map[string]ApplianceVersion
+	versionErrs map[string]error
+
+	// unitsMu guards the managed units already confirmed to be registered with the Central Manager
+	unitsMu    contextMutex
+	validUnits map[string]struct{}
 }
 
 func NewClient(host, port string) *Client {
 	return &Client{
-		Host: host,
-		port: port,
+		Host:           host,
+		port:           port,
+		protocol:       "https",
+		retry:          DefaultRetryConfig(),
+		requestTimeout: DefaultRequestTimeout,
+		proxy:          http.ProxyFromEnvironment,
+	}
+}
+
+// ConfigureTLS sets the provider level TLS settings used by every secure client created from c
+func (c *Client) ConfigureTLS(config TLSConfig) error {
+	tlsConfig, err := config.Build()
+	if err != nil {
+		return err
+	}
+
+	c.tlsConfig = tlsConfig
+	c.resetHTTPClients()
+	return nil
+}
+
+// ConfigureRetry sets how requests failing with transient errors are retried
+func (c *Client) ConfigureRetry(config RetryConfig) {
+	c.retry = config
+	c.resetHTTPClients()
+}
+
+// baseTLSConfig returns a copy of the provider level TLS settings, defaulting to verification against the system roots
+func (c *Client) baseTLSConfig() *tls.Config {
+	if c.tlsConfig == nil {
+		return &tls.Config{MinVersion: tls.VersionTLS12}
 	}
+
+	return c.tlsConfig.Clone()
 }
 
 type OauthTokenResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the lifetime of the access token in seconds, zero when the appliance does not report it
+	ExpiresIn int64 `json:"expires_in"`
+	// User is who the token was issued to, the user of the password grant and the OAuth client otherwise.
+	// It is not part of the token response.
+	User string `json:"-"`
 }
 
-func (c *Client) generateAccessToken(ctx context.Context, httpClient *http.Client, clientSecret, username, password, clientId string) (*OauthTokenResponse, error) {
-	parsedUrl, err := url.Parse(fmt.Sprintf("%s://%s:%s/oauth/token", c.protocol, c.Host, c.port))
+// generateAccessToken requests an access token with the grant type configured in credentials
+func (c *Client) generateAccessToken(ctx context.Context, httpClient *http.Client, credentials Credentials) (*OauthTokenResponse, error) {
+	credentials, err := credentials.resolve(ctx)
 	if err != nil {
-		tflog.Error(ctx, "failed to parse url "+err.Error())
+		return nil, err
+	}
+	if err := credentials.Validate(); err != nil {
 		return nil, err
 	}
 
-	queryParams := parsedUrl.Query()
-	queryParams.Set("client_id", clientId)
-	queryParams.Set("client_secret", clientSecret)
-	queryParams.Set("password", password)
-	queryParams.Set("username", username)
-	queryParams.Set("grant_type", "password")
+	form := url.Values{}
+	form.Set("grant_type", credentials.grantType())
+	switch credentials.grantType() {
+	case GrantTypePassword:
+		form.Set("username", credentials.Username)
+		form.Set("password", credentials.Password)
+	case GrantTypeRefreshToken:
+		form.Set("refresh_token", credentials.RefreshToken)
+	}
+
+	return c.requestToken(ctx, httpClient, credentials, form)
+}
 
-	parsedUrl.RawQuery = queryParams.Encode()
-	tflog.Info(ctx, "parsed url "+parsedUrl.String())
-	req, err := http.NewRequest("POST", parsedUrl.String(), nil)
+// refreshAccessToken exchanges a refresh token previously issued by the appliance for a new access token
+func (c *Client) refreshAccessToken(ctx context.Context, httpClient *http.Client, credentials Credentials, refreshToken string) (*OauthTokenResponse, error) {
+	credentials, err := credentials.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", GrantTypeRefreshToken)
+	form.Set("refresh_token", refreshToken)
+
+	return c.requestToken(ctx, httpClient, credentials, form)
+}
+
+// requestToken calls the OAuth token endpoint with the grant parameters in form. Credentials are sent in the
+// form encoded request body, or the client credentials as basic authentication, so that they never appear in
+// the URL where proxies and logs would record them.
+func (c *Client) requestToken(ctx context.Context, httpClient *http.Client, credentials Credentials, form url.Values) (*OauthTokenResponse, error) {
+	tokenUrl := fmt.Sprintf("%s://%s:%s%s", c.protocol, c.Host, c.port, oauthTokenPath)
+
+	if credentials.ClientAuth != ClientAuthBasic {
+		form.Set("client_id", credentials.ClientID)
+		if credentials.ClientSecret != "" {
+			form.Set("client_secret", credentials.ClientSecret)
+		}
+	}
+
+	tflog.Debug(ctx, "requesting access token", map[string]any{"grant_type": form.Get("grant_type")})
+	// Requesting a token has no side effects on the appliance so it is always safe to retry
+	req, err := http.NewRequestWithContext(WithIdempotent(ctx), "POST", tokenUrl, strings.NewReader(form.Encode()))
 	if err != nil {
 		tflog.Error(ctx, "failed to create new request "+err.Error())
 		return nil, err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if credentials.ClientAuth == ClientAuthBasic {
+		// Basic authentication credentials are form encoded first, see RFC 6749 section 2.3.1
+		req.SetBasicAuth(url.QueryEscape(credentials.ClientID), url.QueryEscape(credentials.ClientSecret))
+	}
 
 	res, err := httpClient.Do(req)
 	if err != nil {
@@ -68,15 +178,15 @@ func (c *Client) generateAccessToken(ctx context.Context, httpClient *http.Clien
 		return nil, err
 	}
 
-	if res.StatusCode == http.StatusBadRequest {
-		tflog.Error(ctx, "invalid credentials for access token. Please review your client_id and client_secret values")
-		return nil, fmt.Errorf("invalid credentials for access token. Please review your client_id and client_secret values")
-	}
-
 	defer res.Body.Close()
-	body, err := io.ReadAll(res.Body)
+
+	body, err := checkResponse(res)
 	if err != nil {
-		tflog.Error(ctx, "failed to read body "+err.Error())
+		if res.StatusCode == http.StatusBadRequest || res.StatusCode == http.StatusUnauthorized {
+			tflog.Error(ctx, "invalid credentials for access token. Please review your client_id and client_secret values")
+			return nil, fmt.Errorf("invalid credentials for access token. Please review your client_id and client_secret values: %w", err)
+		}
+		tflog.Error(ctx, "failed to generate access token "+err.Error())
 		return nil, err
 	}
 
@@ -85,6 +195,10 @@ func (c *Client) generateAccessToken(ctx context.Context, httpClient *http.Clien
 		tflog.Error(ctx, "failed to parse body "+err.Error())
 		return nil, err
 	}
+	otr.User = credentials.ClientID
+	if credentials.grantType() == GrantTypePassword {
+		otr.User = credentials.Username
+	}
 
 	return otr, nil
 }
@@ -104,138 +218,60 @@ type ImportProfilesFromFileResponse struct {
 // 1. Multipart upload: If pathToFile exists locally, uploads file content via multipart/form-data
 // 2. Legacy SFTP: If pathToFile doesn't exist locally (server path), sends JSON with server path
 func (c *Client) ImportProfilesFromFile(ctx context.Context, httpClient *http.Client, accessToken, pathToFile string, updateMode bool) error {
-	// Prepare the request URL
-	importProfilesFromFileUrl := fmt.Sprintf("%s://%s:%s/restAPI/importProfilesFromFile", c.protocol, c.Host, c.port)
+	cmd := Command{Method: http.MethodPost, Name: "importProfilesFromFile"}
 
 	// Detect if this is a local file path or server path by checking if file exists locally
 	_, err := os.Stat(pathToFile)
 	isLocalFile := err == nil
 
-	var req *http.Request
-
 	if isLocalFile {
 		// NEW METHOD: Multipart upload for local files
 		tflog.Info(ctx, "Detected local file - using multipart upload", map[string]any{"pathToFile": pathToFile})
 
-		file, err := os.Open(pathToFile)
-		if err != nil {
-			tflog.Error(ctx, "Error opening local file", map[string]any{"pathToFile": pathToFile, "error": err.Error()})
-			return fmt.Errorf("error opening file %s: %w", pathToFile, err)
-		}
-		defer file.Close()
-
-		// Create multipart form data
-		body := &bytes.Buffer{}
-		writer := multipart.NewWriter(body)
-
-		// Add the file to the multipart form
-		part, err := writer.CreateFormFile("path", filepath.Base(pathToFile))
-		if err != nil {
-			return fmt.Errorf("error creating form file: %w", err)
-		}
-
-		_, err = io.Copy(part, file)
-		if err != nil {
-			return fmt.Errorf("error copying file content: %w", err)
-		}
-
-		// Add updateMode parameter
-		updateModeStr := "false"
-		if updateMode {
-			updateModeStr = "true"
-		}
-		err = writer.WriteField("updateMode", updateModeStr)
-		if err != nil {
-			return fmt.Errorf("error writing updateMode field: %w", err)
-		}
-
-		// Add TestConnections parameter
-		err = writer.WriteField("TestConnections", "false")
-		if err != nil {
-			return fmt.Errorf("error writing TestConnections field: %w", err)
-		}
-
-		err = writer.Close()
-		if err != nil {
-			return fmt.Errorf("error closing multipart writer: %w", err)
+		// The oldest version accepting uploads is not documented, the version is only logged to help diagnose
+		// appliances that look for the local path on their own file system instead
+		if _, err := c.unitVersion(ctx, httpClient, accessToken, c.targetUnitFor(ctx)); err != nil {
+			tflog.Warn(ctx, "Could not read the appliance version before uploading profiles", map[string]any{"error": err.Error()})
 		}
 
-		// Create the request
-		req, err = http.NewRequestWithContext(ctx, "POST", importProfilesFromFileUrl, body)
+		// The file is streamed while the request is sent, so that large files are not held in memory
+		upload, err := newFileUpload(ctx, "path", pathToFile,
+			formField{Name: "updateMode", Value: strconv.FormatBool(updateMode)},
+			formField{Name: "TestConnections", Value: "false"},
+		)
 		if err != nil {
-			return fmt.Errorf("error creating request: %w", err)
+			tflog.Error(ctx, "Error preparing upload of local file", map[string]any{"pathToFile": pathToFile, "error": err.Error()})
+			return err
 		}
 
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-		req.Header.Set("Content-Type", writer.FormDataContentType())
-
-		tflog.Info(ctx, "Sending multipart upload request", map[string]any{
-			"url":         importProfilesFromFileUrl,
-			"contentType": writer.FormDataContentType(),
-		})
+		cmd.Body = upload
+		cmd.ContentType = upload.ContentType()
 	} else {
 		// LEGACY METHOD: JSON API with server path (for SFTP)
 		tflog.Info(ctx, "File not found locally - using legacy SFTP method with server path", map[string]any{"pathToFile": pathToFile})
 
-		requestBody := ImportProfilesFromFileRequest{
+		cmd.Params = ImportProfilesFromFileRequest{
 			UpdateMode: updateMode,
 			Path:       pathToFile,
 		}
-
-		jsonBody, err := json.Marshal(requestBody)
-		if err != nil {
-			return fmt.Errorf("error marshaling request body: %w", err)
-		}
-
-		req, err = http.NewRequestWithContext(ctx, "POST", importProfilesFromFileUrl, bytes.NewBuffer(jsonBody))
-		if err != nil {
-			return fmt.Errorf("error creating request: %w", err)
-		}
-
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-		req.Header.Set("Content-Type", "application/json")
-
-		tflog.Info(ctx, "Sending JSON request with server path", map[string]any{
-			"url":        importProfilesFromFileUrl,
-			"serverPath": pathToFile,
-		})
 	}
 
-	// Send the request
-	resp, err := httpClient.Do(req)
+	resp, err := c.Execute(ctx, httpClient, accessToken, cmd)
 	if err != nil {
-		return fmt.Errorf("error sending request: %w", err)
-	}
-
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("error reading response body: %w", err)
-	}
-
-	tflog.Info(ctx, "Received response", map[string]any{
-		"statusCode":   resp.StatusCode,
-		"responseBody": string(responseBody),
-	})
-
-	// Check the response status
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error response from server: %s, status code: %d", string(responseBody), resp.StatusCode)
-	}
-
-	// Parse the response body to check for errors
-	var apiResponse ImportProfilesFromFileResponse
-	if err := json.Unmarshal(responseBody, &apiResponse); err != nil {
-		tflog.Warn(ctx, "failed to parse import profiles response, continuing anyway: "+err.Error())
-		tflog.Debug(ctx, "sent request to import profiles from file response "+string(responseBody))
-		return nil
-	}
-
-	tflog.Debug(ctx, "sent request to import profiles from file response "+string(responseBody))
-
-	// Check if the Message field contains an error
-	if apiResponse.Message != "" && containsErrorKeywords(apiResponse.Message) {
-		return fmt.Errorf("import profiles failed: %s", apiResponse.Message)
+		return fmt.Errorf("import profiles failed: %w", err)
+	}
+
+	// Like bulk install, the API may return a 200 status but still report a failure in the Message field
+	if parsedBody, err := decode[ImportProfilesFromFileResponse](resp); err == nil {
+		if containsErrorKeywords(parsedBody.Message) {
+			return fmt.Errorf("import profiles failed: %w", &APIError{
+				StatusCode: resp.StatusCode,
+				ID:         parsedBody.ID,
+				Message:    parsedBody.Message,
+				Endpoint:   "POST " + restAPIPath + cmd.Name,
+				Body:       string(resp.Body),
+			})
+		}
 	}
 
 	return nil
@@ -254,9 +290,9 @@ func containsErrorKeywords(message string) bool {
 		"does not exist",
 	}
 
-	messageLower := bytes.ToLower([]byte(message))
+	messageLower := strings.ToLower(message)
 	for _, keyword := range errorKeywords {
-		if bytes.Contains(messageLower, []byte(keyword)) {
+		if strings.Contains(messageLower, keyword) {
 			return true
 		}
 	}
@@ -281,68 +317,35 @@ type bulkInstallConnectorResponse struct {
 
 // BulkInstallConnector installs connectors in bulk
 func (c *Client) BulkInstallConnector(ctx context.Context, httpClient *http.Client, accessToken, udcName, gdpMuHost string) error {
-	// Create the request URL
-	bulkInstallUrl := fmt.Sprintf("%s://%s:%s/restAPI/bulkInstall", c.protocol, c.Host, c.port)
-	// Create the request body
-	requestBody := &bulkInstallRequestBody{
-		ProfileNames: udcName,
-		Hosts:        gdpMuHost,
-	}
-
-	// Marshal the request body to JSON
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("error marshaling request body: %w", err)
-	}
-	tflog.Debug(ctx, "parsed install connector url "+bulkInstallUrl)
-	tflog.Debug(ctx, "parsed install connector body "+string(jsonBody))
-
-	// Create the HTTP request
-	req, err := http.NewRequest("POST", bulkInstallUrl, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send the request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error sending request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check the response status
-	body, err := io.ReadAll(resp.Body)
+	cmd := Command{
+		Method: http.MethodPost,
+		Name:   "bulkInstall",
+		Params: &bulkInstallRequestBody{
+			ProfileNames: udcName,
+			Hosts:        gdpMuHost,
+		},
+		// Installing the same profile on the same hosts again is harmless so it can be retried
+		Idempotent: true,
+	}
+
+	resp, err := c.Execute(ctx, httpClient, accessToken, cmd)
 	if err != nil {
-		return fmt.Errorf("error reading response body: %w", err)
-	}
-
-	parsedBody := new(bulkInstallConnectorResponse)
-	if err = json.Unmarshal(body, parsedBody); err != nil {
-		tflog.Warn(ctx, "failed to parse bulk install response, continuing anyway: "+err.Error())
-		tflog.Debug(ctx, "install connector response "+string(body))
-		return nil
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error response from server: %s, status code: %d", string(body), resp.StatusCode)
-	}
-
-	// Check for known error messages in the predefined map
-	if _, k := bulkInstallErrors[parsedBody.Message]; k {
-		return fmt.Errorf("bulk install failed: %s", parsedBody.Message)
-	}
-
-	// Also check if the Message field contains error keywords
-	// The API may return ID="0" but still have an error in the Message field
-	if parsedBody.Message != "" && containsErrorKeywords(parsedBody.Message) {
-		return fmt.Errorf("bulk install failed: %s", parsedBody.Message)
+		return fmt.Errorf("bulk install failed: %w", err)
+	}
+
+	// The API may return ID="0" with a 200 status but still report a failure in the Message field
+	if parsedBody, err := decode[bulkInstallConnectorResponse](resp); err == nil {
+		if _, k := bulkInstallErrors[parsedBody.Message]; k || containsErrorKeywords(parsedBody.Message) {
+			return fmt.Errorf("bulk install failed: %w", &APIError{
+				StatusCode: resp.StatusCode,
+				ID:         parsedBody.ID,
+				Message:    parsedBody.Message,
+				Endpoint:   "POST " + restAPIPath + cmd.Name,
+				Body:       string(resp.Body),
+			})
+		}
 	}
 
-	tflog.Debug(ctx, "install connector response "+string(body))
 	return nil
 }
 
@@ -354,61 +357,16 @@ type RegisterDatasourceResponse struct {
 }
 
 func (c *Client) RegisterVADataSource(ctx context.Context, httpClient *http.Client, accessToken string, payload []byte) error {
-	// Create the request URL
-	registerURL := fmt.Sprintf("%s://%s:%s/restAPI/datasource", c.protocol, c.Host, c.port)
-	tflog.Debug(ctx, "register data source url "+registerURL)
-	tflog.Debug(ctx, fmt.Sprintf("register data source payload %s", string(payload)))
-	tflog.Debug(ctx, fmt.Sprintf("register data source token  %s", accessToken))
-
-	test := make(map[string]interface{})
-	err := json.Unmarshal(payload, &test)
-	if err != nil {
-		panic(err)
-	}
-
-	payloadJson, err := json.Marshal(test)
-	if err != nil {
-		panic(err)
-	}
-
-	tflog.Debug(ctx, "string(payloadJson)")
-	tflog.Debug(ctx, string(payloadJson))
-
-	tflog.Debug(ctx, fmt.Sprintf("json output "+string(payloadJson)))
-	// Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", registerURL, bytes.NewReader(payloadJson))
-	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Could not create request: %s", err))
-		return err
-	}
-
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-
-	// Send the request
-	res, err := httpClient.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Could not send request: %s", err))
-		return err
-	}
-	defer res.Body.Close()
-
-	// Parse the response
-	var apiResp RegisterDatasourceResponse
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Could not parse response: %s. Body %s", err, string(body)))
-		return err
+	if !json.Valid(payload) {
+		return fmt.Errorf("invalid register data source payload: not a JSON document")
 	}
-	tflog.Debug(ctx, "register data source response "+string(body))
 
-	// Check for errors
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		tflog.Error(ctx, fmt.Sprintf("Status code: %d, Error: %s, Message: %s", res.StatusCode, apiResp.Error, apiResp.Message))
-		return err
-	}
-	return nil
+	_, err := c.Execute(ctx, httpClient, accessToken, Command{
+		Method: http.MethodPost,
+		Name:   "datasource",
+		Params: json.RawMessage(payload),
+	})
+	return err
 }
 
 // VAConfigResponse represents the response from the API
@@ -420,49 +378,18 @@ type VAConfigResponse struct {
 
 // ConfigureVADataSource configures the va datasource
 func (c *Client) ConfigureVADataSource(ctx context.Context, httpClient *http.Client, accessToken string, payload []byte) error {
-	// Convert payload to JSON
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		tflog.Debug(ctx, fmt.Sprintf("Could not marshal payload: %s", err))
-		return err
-	}
-
-	// Create the request URL
-	configURL := fmt.Sprintf("%s://%s:%s/restAPI/va/config", c.protocol, c.Host, c.port)
-
-	// Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", configURL, bytes.NewReader(payloadBytes))
-	if err != nil {
-		tflog.Debug(ctx, fmt.Sprintf("Could not create request: %s", err))
-		return err
-	}
-
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-
-	// Send the request
-	httpResp, err := httpClient.Do(httpReq)
-	if err != nil {
-		tflog.Debug(ctx, fmt.Sprintf("Could not send request: %s", err))
-		return err
-	}
-	defer httpResp.Body.Close()
-
-	// Parse the response
-	var apiResp VAConfigResponse
-	if err := json.NewDecoder(httpResp.Body).Decode(&apiResp); err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Could not parse response: %s", err))
-		return err
+	if !json.Valid(payload) {
+		return fmt.Errorf("invalid configure VA data source payload: not a JSON document")
 	}
 
-	// Check for errors
-	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusCreated {
-		tflog.Debug(ctx, fmt.Sprintf("Status code: %d, Error: %s, Message: %s",
-			httpResp.StatusCode, apiResp.Error, apiResp.Message))
-		return err
-	}
-	return nil
+	_, err := c.Execute(ctx, httpClient, accessToken, Command{
+		Method: http.MethodPost,
+		Name:   "va/config",
+		Params: json.RawMessage(payload),
+		// The configuration is replaced as a whole so it can be retried
+		Idempotent: true,
+	})
+	return err
 }
 
 // NotificationsResponse represents the response from the API
@@ -474,39 +401,16 @@ type NotificationsResponse struct {
 
 // ConfigureVANotifications configure va notifications
 func (c *Client) ConfigureVANotifications(ctx context.Context, httpClient *http.Client, accessToken string, payload []byte) error {
-	// Create the request URL
-	notificationsURL := fmt.Sprintf("%s://%s:%s/restAPI/notifications", c.protocol, c.Host, c.port)
-
-	// Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", notificationsURL, bytes.NewReader(payload))
-	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Could not create request: %s", err))
-		return err
+	if !json.Valid(payload) {
+		return fmt.Errorf("invalid configure VA notifications payload: not a JSON document")
 	}
 
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-
-	// Send the request
-	httpResp, err := httpClient.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Could not send request: %s", err))
-		return err
-	}
-	defer httpResp.Body.Close()
-
-	// Parse the response
-	var apiResp NotificationsResponse
-	if err := json.NewDecoder(httpResp.Body).Decode(&apiResp); err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Could not parse response: %s", err))
-		return err
-	}
-
-	// Check for errors
-	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusCreated {
-		tflog.Error(ctx, fmt.Sprintf("Status code: %d, Error: %s, Message: %s", httpResp.StatusCode, apiResp.Error, apiResp.Message))
-		return err
-	}
-	return nil
+	_, err := c.Execute(ctx, httpClient, accessToken, Command{
+		Method: http.MethodPost,
+		Name:   "notifications",
+		Params: json.RawMessage(payload),
+		// The configuration is replaced as a whole so it can be retried
+		Idempotent: true,
+	})
+	return err
 }
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -66,32 +68,75 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestGenerateAccessToken(t *testing.T) {
+	passwordCredentials := Credentials{ClientID: "client1", ClientSecret: "secret", Username: "user", Password: "pass"}
+
 	// Test cases
 	testCases := []struct {
-		name           string
-		clientSecret   string
-		username       string
-		password       string
-		serverStatus   int
-		serverResponse string
-		expectError    bool
-		expectedToken  string
+		name            string
+		credentials     Credentials
+		expectedForm    url.Values
+		expectedBasic   []string
+		serverStatus    int
+		serverResponse  string
+		expectError     bool
+		expectedToken   string
+		expectedExpiry  int64
+		expectedRefresh string
 	}{
 		{
 			name:           "Successful token generation",
-			clientSecret:   "secret",
-			username:       "user",
-			password:       "pass",
+			credentials:    passwordCredentials,
+			expectedForm:   url.Values{"grant_type": {"password"}, "client_id": {"client1"}, "client_secret": {"secret"}, "username": {"user"}, "password": {"pass"}},
 			serverStatus:   http.StatusOK,
 			serverResponse: `{"access_token":"test-token"}`,
 			expectError:    false,
 			expectedToken:  "test-token",
 		},
+		{
+			name:            "Token with expiry and refresh token",
+			credentials:     passwordCredentials,
+			expectedForm:    url.Values{"grant_type": {"password"}, "client_id": {"client1"}, "client_secret": {"secret"}, "username": {"user"}, "password": {"pass"}},
+			serverStatus:    http.StatusOK,
+			serverResponse:  `{"access_token":"test-token","refresh_token":"refresh-token","expires_in":3600}`,
+			expectError:     false,
+			expectedToken:   "test-token",
+			expectedExpiry:  3600,
+			expectedRefresh: "refresh-token",
+		},
+		{
+			name:           "Client credentials grant",
+			credentials:    Credentials{GrantType: GrantTypeClientCredentials, ClientID: "client1", ClientSecret: "secret"},
+			expectedForm:   url.Values{"grant_type": {"client_credentials"}, "client_id": {"client1"}, "client_secret": {"secret"}},
+			serverStatus:   http.StatusOK,
+			serverResponse: `{"access_token":"test-token"}`,
+			expectedToken:  "test-token",
+		},
+		{
+			name:           "Refresh token grant",
+			credentials:    Credentials{GrantType: GrantTypeRefreshToken, ClientID: "client1", RefreshToken: "refresh-token"},
+			expectedForm:   url.Values{"grant_type": {"refresh_token"}, "client_id": {"client1"}, "refresh_token": {"refresh-token"}},
+			serverStatus:   http.StatusOK,
+			serverResponse: `{"access_token":"test-token"}`,
+			expectedToken:  "test-token",
+		},
+		{
+			name:           "Client basic authentication",
+			credentials:    Credentials{GrantType: GrantTypeClientCredentials, ClientID: "client1", ClientSecret: "s3cr:t", ClientAuth: ClientAuthBasic},
+			expectedForm:   url.Values{"grant_type": {"client_credentials"}},
+			expectedBasic:  []string{"client1", "s3cr%3At"},
+			serverStatus:   http.StatusOK,
+			serverResponse: `{"access_token":"test-token"}`,
+			expectedToken:  "test-token",
+		},
+		{
+			name:        "Missing password",
+			credentials: Credentials{ClientID: "client1", ClientSecret: "secret", Username: "user"},
+			expectError: true,
+		},
 		{
 			name:           "Server error",
-			clientSecret:   "secret",
-			username:       "user",
-			password:       "pass",
+			credentials:    passwordCredentials,
+			expectedForm:   url.Values{"grant_type": {"password"}, "client_id": {"client1"}, "client_secret": {"secret"}, "username": {"user"}, "password": {"pass"}},
 			serverStatus:   http.StatusInternalServerError,
 			serverResponse: `{"error":"internal server error"}`,
 			expectError:    true,
@@ -99,9 +144,8 @@ func TestGenerateAccessToken(t *testing.T) {
 		},
 		{
 			name:           "Invalid JSON response",
-			clientSecret:   "secret",
-			username:       "user",
-			password:       "pass",
+			credentials:    passwordCredentials,
+			expectedForm:   url.Values{"grant_type": {"password"}, "client_id": {"client1"}, "client_secret": {"secret"}, "username": {"user"}, "password": {"pass"}},
 			serverStatus:   http.StatusOK,
 			serverResponse: `invalid-json`,
 			expectError:    true,
@@ -118,19 +162,25 @@ func TestGenerateAccessToken(t *testing.T) {
 					t.Errorf("Expected POST request, got %s", r.Method)
 				}
 
-				// Check query parameters
-				query := r.URL.Query()
-				if query.Get("client_id") != "client1" {
-					t.Errorf("Expected client_id=client1, got %s", query.Get("client_id"))
+				// Credentials must never be sent in the URL
+				if r.URL.RawQuery != "" {
+					t.Errorf("Expected no query parameters, got %s", r.URL.RawQuery)
 				}
-				if query.Get("client_secret") != tc.clientSecret {
-					t.Errorf("Expected client_secret=%s, got %s", tc.clientSecret, query.Get("client_secret"))
+
+				// Check form parameters
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("Error parsing form: %v", err)
 				}
-				if query.Get("username") != tc.username {
-					t.Errorf("Expected username=%s, got %s", tc.username, query.Get("username"))
+				if !reflect.DeepEqual(r.PostForm, tc.expectedForm) {
+					t.Errorf("Expected form %v, got %v", tc.expectedForm, r.PostForm)
 				}
-				if query.Get("password") != tc.password {
-					t.Errorf("Expected password=%s, got %s", tc.password, query.Get("password"))
+
+				username, password, ok := r.BasicAuth()
+				if tc.expectedBasic == nil && ok {
+					t.Error("Expected no basic authentication")
+				}
+				if tc.expectedBasic != nil && (username != tc.expectedBasic[0] || password != tc.expectedBasic[1]) {
+					t.Errorf("Expected basic authentication %v, got %s:%s", tc.expectedBasic, username, password)
 				}
 
 				// Set response status and body
@@ -138,8 +188,8 @@ func TestGenerateAccessToken(t *testing.T) {
 				_, err := w.Write([]byte(tc.serverResponse))
 
 				// Check error
-				if err == nil {
-					t.Error("Expected error but got nil")
+				if err != nil {
+					t.Errorf("Error writing response: %v", err)
 				}
 			}))
 			defer server.Close()
@@ -151,13 +201,14 @@ func TestGenerateAccessToken(t *testing.T) {
 
 			// Create client
 			client := &Client{
-				Host: host,
-				port: port,
+				protocol: "http",
+				Host:     host,
+				port:     port,
 			}
 
 			// Call the function
 			ctx := context.Background()
-			result, err := client.generateAccessToken(ctx, server.Client(), tc.clientSecret, tc.username, tc.password, "test_client_id")
+			result, err := client.generateAccessToken(ctx, server.Client(), tc.credentials)
 
 			// Check error
 			if tc.expectError && err == nil {
@@ -171,8 +222,16 @@ func TestGenerateAccessToken(t *testing.T) {
 			if !tc.expectError {
 				if result == nil {
 					t.Error("Expected result but got nil")
-				} else if result.AccessToken != tc.expectedToken {
-					t.Errorf("Expected token %s, got %s", tc.expectedToken, result.AccessToken)
+				} else {
+					if result.AccessToken != tc.expectedToken {
+						t.Errorf("Expected token %s, got %s", tc.expectedToken, result.AccessToken)
+					}
+					if result.ExpiresIn != tc.expectedExpiry {
+						t.Errorf("Expected expires_in %d, got %d", tc.expectedExpiry, result.ExpiresIn)
+					}
+					if result.RefreshToken != tc.expectedRefresh {
+						t.Errorf("Expected refresh token %s, got %s", tc.expectedRefresh, result.RefreshToken)
+					}
 				}
 			}
 		})
@@ -187,6 +246,7 @@ func TestImportProfilesFromFile(t *testing.T) {
 		pathToFile   string
 		updateMode   bool
 		serverStatus int
+		responseBody string
 		expectError  bool
 	}{
 		{
@@ -195,8 +255,27 @@ func TestImportProfilesFromFile(t *testing.T) {
 			pathToFile:   "/path/to/file.json",
 			updateMode:   true,
 			serverStatus: http.StatusOK,
+			responseBody: `{"ID":"0","Message":"Profiles imported from /path/to/file.json"}`,
 			expectError:  false,
 		},
+		{
+			name:         "Failure reported with a 200 status",
+			accessToken:  "test-token",
+			pathToFile:   "/path/to/file.json",
+			updateMode:   true,
+			serverStatus: http.StatusOK,
+			responseBody: `{"ID":"0","Message":"Import profiles failed: file not found"}`,
+			expectError:  true,
+		},
+		{
+			name:         "Failure reported with another message",
+			accessToken:  "test-token",
+			pathToFile:   "/path/to/file.json",
+			updateMode:   true,
+			serverStatus: http.StatusOK,
+			responseBody: `{"ID":"0","Message":"Unable to parse the profiles in /path/to/file.json"}`,
+			expectError:  true,
+		},
 		{
 			name:         "Server error",
 			accessToken:  "test-token",
@@ -245,6 +324,7 @@ func TestImportProfilesFromFile(t *testing.T) {
 
 				// Set response status
 				w.WriteHeader(tc.serverStatus)
+				w.Write([]byte(tc.responseBody))
 			}))
 			defer server.Close()
 
@@ -284,6 +364,7 @@ func TestBulkInstallConnector(t *testing.T) {
 		udcName      string
 		gdpMuHost    string
 		serverStatus int
+		responseBody string
 		expectError  bool
 	}{
 		{
@@ -294,6 +375,24 @@ func TestBulkInstallConnector(t *testing.T) {
 			serverStatus: http.StatusOK,
 			expectError:  false,
 		},
+		{
+			name:         "Unknown hosts reported with a 200 status",
+			accessToken:  "test-token",
+			udcName:      "connector-profile",
+			gdpMuHost:    "host1.example.com",
+			serverStatus: http.StatusOK,
+			responseBody: `{"ID":"0","Message":"One or more of the specified hosts could not be found"}`,
+			expectError:  true,
+		},
+		{
+			name:         "Failure reported with another message",
+			accessToken:  "test-token",
+			udcName:      "connector-profile",
+			gdpMuHost:    "host1.example.com",
+			serverStatus: http.StatusOK,
+			responseBody: `{"ID":"0","Message":"Installation failed for profile connector-profile"}`,
+			expectError:  true,
+		},
 		{
 			name:         "Server error",
 			accessToken:  "test-token",
@@ -342,6 +441,7 @@ func TestBulkInstallConnector(t *testing.T) {
 
 				// Set response status
 				w.WriteHeader(tc.serverStatus)
+				w.Write([]byte(tc.responseBody))
 			}))
 			defer server.Close()
 
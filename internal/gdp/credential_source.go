@@ -0,0 +1,145 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// CredentialProcessTimeout bounds a single run of the credential process
+const CredentialProcessTimeout = time.Minute
+
+// maxProcessStderr is the amount of credential process error output included in errors
+const maxProcessStderr = 1024
+
+// processCredentials is the JSON document a credential process prints on its standard output
+type processCredentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+}
+
+// hasExternalSecrets reports whether some credentials are read from files or the credential process
+func (c Credentials) hasExternalSecrets() bool {
+	return c.Process != "" || c.PasswordFile != "" || c.ClientSecretFile != ""
+}
+
+// resolve returns the credentials with the secrets read from PasswordFile and ClientSecretFile and the values
+// printed by Process. Values already set take precedence over files, which take precedence over the process.
+// They are read every time a token is requested, so that rotated secrets are picked up.
+func (c Credentials) resolve(ctx context.Context) (Credentials, error) {
+	if !c.hasExternalSecrets() {
+		return c, nil
+	}
+
+	resolved := c
+	resolved.Process, resolved.PasswordFile, resolved.ClientSecretFile = "", "", ""
+
+	var err error
+	if resolved.Password == "" && c.PasswordFile != "" {
+		if resolved.Password, err = readSecretFile(c.PasswordFile); err != nil {
+			return c, err
+		}
+	}
+	if resolved.ClientSecret == "" && c.ClientSecretFile != "" {
+		if resolved.ClientSecret, err = readSecretFile(c.ClientSecretFile); err != nil {
+			return c, err
+		}
+	}
+
+	if c.Process != "" {
+		process, err := runCredentialProcess(ctx, c.Process)
+		if err != nil {
+			return c, err
+		}
+		for _, field := range []struct {
+			value *string
+			from  string
+		}{
+			{&resolved.ClientID, process.ClientID},
+			{&resolved.ClientSecret, process.ClientSecret},
+			{&resolved.Username, process.Username},
+			{&resolved.Password, process.Password},
+		} {
+			if *field.value == "" {
+				*field.value = field.from
+			}
+		}
+	}
+
+	return resolved, nil
+}
+
+// readSecretFile returns the content of a file holding a single secret, without its trailing line break
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading secret file: %w", err)
+	}
+
+	secret := strings.TrimRight(string(data), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("secret file %s is empty", path)
+	}
+	return secret, nil
+}
+
+// runCredentialProcess runs command through the system shell and parses the credentials it prints as JSON.
+// Its output is never logged.
+func runCredentialProcess(ctx context.Context, command string) (*processCredentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, CredentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	tflog.Debug(ctx, "running credential process")
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("credential process did not complete: %w", ctxErr)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if len(message) > maxProcessStderr {
+			message = message[:maxProcessStderr] + "..."
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && message != "" {
+			return nil, fmt.Errorf("credential process failed with exit code %d: %s", exitErr.ExitCode(), message)
+		}
+		return nil, fmt.Errorf("credential process failed: %w", err)
+	}
+
+	var credentials processCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		// The output holds secrets, so only the position of the syntax error is reported
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, fmt.Errorf("credential process output is not valid JSON, error at offset %d", syntaxErr.Offset)
+		}
+		return nil, fmt.Errorf("invalid credential process output, expected client_id, client_secret, username and password: %w", err)
+	}
+	if credentials == (processCredentials{}) {
+		return nil, errors.New("credential process printed no credentials, expected client_id, client_secret, username or password")
+	}
+
+	return &credentials, nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func writeSecretFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCredentialsResolve(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential process tests use a POSIX shell")
+	}
+
+	passwordFile := writeSecretFile(t, "password", "file-password\n")
+	secretFile := writeSecretFile(t, "secret", "file-secret\r\n")
+	process := `printf '{"client_id":"process-id","client_secret":"process-secret","username":"process-user","password":"process-password"}'`
+
+	testCases := []struct {
+		name          string
+		credentials   Credentials
+		expected      Credentials
+		expectedError string
+	}{
+		{
+			name:        "Secret files",
+			credentials: Credentials{ClientID: "id", Username: "user", PasswordFile: passwordFile, ClientSecretFile: secretFile},
+			expected:    Credentials{ClientID: "id", ClientSecret: "file-secret", Username: "user", Password: "file-password"},
+		},
+		{
+			name:        "Credential process",
+			credentials: Credentials{Process: process},
+			expected:    Credentials{ClientID: "process-id", ClientSecret: "process-secret", Username: "process-user", Password: "process-password"},
+		},
+		{
+			name:        "Precedence",
+			credentials: Credentials{ClientID: "id", PasswordFile: passwordFile, Process: process},
+			expected:    Credentials{ClientID: "id", ClientSecret: "process-secret", Username: "process-user", Password: "file-password"},
+		},
+		{
+			name:          "Missing file",
+			credentials:   Credentials{PasswordFile: filepath.Join(t.TempDir(), "missing")},
+			expectedError: "error reading secret file",
+		},
+		{
+			name:          "Empty file",
+			credentials:   Credentials{ClientSecretFile: writeSecretFile(t, "empty", "\n")},
+			expectedError: "is empty",
+		},
+		{
+			name:          "Failing process",
+			credentials:   Credentials{Process: "echo 'vault is sealed' >&2; exit 3"},
+			expectedError: "exit code 3: vault is sealed",
+		},
+		{
+			name:          "Invalid output",
+			credentials:   Credentials{Process: "echo password=hunter2"},
+			expectedError: "not valid JSON",
+		},
+		{
+			name:          "No credentials",
+			credentials:   Credentials{Process: "echo '{}'"},
+			expectedError: "printed no credentials",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved, err := tc.credentials.resolve(context.Background())
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				if strings.Contains(err.Error(), "hunter2") {
+					t.Errorf("Expected the process output not to be reported, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if resolved != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, resolved)
+			}
+		})
+	}
+}
+
+func TestCredentialsValidateExternalSecrets(t *testing.T) {
+	if err := (Credentials{ClientID: "id", Username: "user", PasswordFile: "password", ClientSecretFile: "secret"}).Validate(); err != nil {
+		t.Errorf("Expected secret files to satisfy the password grant, got %v", err)
+	}
+	if err := (Credentials{Process: "get-credentials"}).Validate(); err != nil {
+		t.Errorf("Expected a credential process to defer validation, got %v", err)
+	}
+	if err := (Credentials{ClientID: "id", PasswordFile: "password"}).Validate(); err == nil {
+		t.Error("Expected missing client_secret and username to be reported")
+	}
+}
+
+func TestGenerateAccessTokenCredentialProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential process tests use a POSIX shell")
+	}
+
+	var gotPassword string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		gotPassword = r.PostForm.Get("password")
+		w.Write([]byte(`{"access_token":"token"}`))
+	}))
+	defer server.Close()
+
+	client := newGuardAPITestClient(server)
+	credentials := Credentials{
+		ClientID:         "id",
+		ClientSecretFile: writeSecretFile(t, "secret", "secret"),
+		Process:          `echo '{"username":"user","password":"process-password"}'`,
+	}
+	otr, err := client.generateAccessToken(context.Background(), server.Client(), credentials)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if otr.AccessToken != "token" || gotPassword != "process-password" {
+		t.Errorf("Expected the password printed by the process to be sent, got %q", gotPassword)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned by every client method when the Guardium Data Protection appliance
+// reports a failure, either through the HTTP status or through the response body
+type APIError struct {
+	// StatusCode is the HTTP status returned by the appliance
+	StatusCode int
+	// ID is the Guardium ID or error code reported in the response, if any
+	ID string
+	// Message is the human readable failure reported by the appliance
+	Message string
+	// Endpoint is the method and path of the failed request, e.g. "POST /restAPI/bulkInstall"
+	Endpoint string
+	// Body is the raw response body
+	Body string
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Body
+	}
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+
+	if e.ID != "" {
+		return fmt.Sprintf("%s returned status %d: %s (ID %s)", e.Endpoint, e.StatusCode, message, e.ID)
+	}
+	return fmt.Sprintf("%s returned status %d: %s", e.Endpoint, e.StatusCode, message)
+}
+
+// IsNotFound reports whether err is an APIError for a missing object
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError for an object that already exists or was changed concurrently
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsUnauthorized reports whether err is an APIError caused by a missing, invalid or expired access token
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsCanceled reports whether err is caused by the operation being interrupted or running out of time, rather
+// than by a failure reported by the appliance. The appliance may still have processed the operation.
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// responseEnvelope holds the fields Guardium uses to report the outcome of a GuardAPI command.
+// Field matching is case-insensitive so both `Message` and `message` are recognized.
+type responseEnvelope struct {
+	ID           json.RawMessage `json:"ID"`
+	Message      string          `json:"Message"`
+	Error        string          `json:"error"`
+	ErrorCode    json.RawMessage `json:"ErrorCode"`
+	ErrorMessage string          `json:"ErrorMessage"`
+}
+
+// checkResponse reads the response body and returns an *APIError when the status is not 2xx,
+// or when a 2xx body carries a non-zero Guardium error code or an `ERR=` message
+func checkResponse(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	var envelope responseEnvelope
+	_ = json.Unmarshal(body, &envelope)
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		ID:         rawString(envelope.ID),
+		Message:    envelope.Message,
+		Body:       string(body),
+	}
+	if resp.Request != nil {
+		apiErr.Endpoint = resp.Request.Method + " " + resp.Request.URL.Path
+	}
+
+	switch {
+	case envelope.ErrorMessage != "":
+		apiErr.Message = envelope.ErrorMessage
+	case envelope.Error != "":
+		apiErr.Message = envelope.Error
+	}
+	// Some commands report success with an `ErrorCode` of 0
+	errorCode := rawString(envelope.ErrorCode)
+	if errorCode == "0" {
+		errorCode = ""
+	}
+	if errorCode != "" {
+		apiErr.ID = errorCode
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return body, apiErr
+	}
+
+	if errorCode != "" || envelope.ErrorMessage != "" || envelope.Error != "" ||
+		strings.HasPrefix(envelope.Message, "ERR=") {
+		return body, apiErr
+	}
+
+	return body, nil
+}
+
+// rawString returns a JSON string or number as a plain string
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	return strings.TrimSpace(string(raw))
+}
@@ -0,0 +1,144 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCheckResponse(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statusCode      int
+		body            string
+		expectError     bool
+		expectedID      string
+		expectedMessage string
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+			body:       `{"ID":"20001","Message":"ID=20001"}`,
+		},
+		{
+			name:       "Success message mentioning errors",
+			statusCode: http.StatusOK,
+			body:       `{"ID":"20001","Message":"Imported 3 profiles, 0 errors"}`,
+		},
+		{
+			name:       "Success with a zero error code",
+			statusCode: http.StatusOK,
+			body:       `{"ID":"20001","ErrorCode":0,"Message":"ID=20001"}`,
+		},
+		{
+			name:       "Success with a zero error code string",
+			statusCode: http.StatusOK,
+			body:       `{"ErrorCode":"0","Message":"ID=20001"}`,
+		},
+		{
+			name:       "Success with an empty error code",
+			statusCode: http.StatusOK,
+			body:       `{"ErrorCode":"","Message":"ID=20001"}`,
+		},
+		{
+			name:       "Success without body",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:            "Server error",
+			statusCode:      http.StatusInternalServerError,
+			body:            `{"error":"internal server error"}`,
+			expectError:     true,
+			expectedMessage: "internal server error",
+		},
+		{
+			name:            "Server error without JSON",
+			statusCode:      http.StatusBadGateway,
+			body:            `<html>Bad Gateway</html>`,
+			expectError:     true,
+			expectedMessage: "",
+		},
+		{
+			name:            "Guardium error code with 200 status",
+			statusCode:      http.StatusOK,
+			body:            `{"ErrorCode":3001,"ErrorMessage":"Datasource not found"}`,
+			expectError:     true,
+			expectedID:      "3001",
+			expectedMessage: "Datasource not found",
+		},
+		{
+			name:            "Guardium ERR message with 200 status",
+			statusCode:      http.StatusOK,
+			body:            `{"ID":0,"Message":"ERR=8 Could not install"}`,
+			expectError:     true,
+			expectedID:      "0",
+			expectedMessage: "ERR=8 Could not install",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tc.statusCode,
+				Body:       io.NopCloser(strings.NewReader(tc.body)),
+				Request:    &http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/restAPI/test"}},
+			}
+
+			body, err := checkResponse(resp)
+			if string(body) != tc.body {
+				t.Errorf("Expected body %q, got %q", tc.body, string(body))
+			}
+
+			if !tc.expectError {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				return
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected *APIError but got: %v", err)
+			}
+			if apiErr.StatusCode != tc.statusCode {
+				t.Errorf("Expected status %d, got %d", tc.statusCode, apiErr.StatusCode)
+			}
+			if apiErr.ID != tc.expectedID {
+				t.Errorf("Expected ID %q, got %q", tc.expectedID, apiErr.ID)
+			}
+			if apiErr.Message != tc.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tc.expectedMessage, apiErr.Message)
+			}
+			if apiErr.Endpoint != "POST /restAPI/test" {
+				t.Errorf("Expected endpoint POST /restAPI/test, got %q", apiErr.Endpoint)
+			}
+		})
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	notFound := fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusNotFound})
+	conflict := &APIError{StatusCode: http.StatusConflict}
+	unauthorized := &APIError{StatusCode: http.StatusUnauthorized}
+
+	if !IsNotFound(notFound) || IsNotFound(conflict) {
+		t.Error("IsNotFound did not match only 404 errors")
+	}
+	if !IsConflict(conflict) || IsConflict(unauthorized) {
+		t.Error("IsConflict did not match only 409 errors")
+	}
+	if !IsUnauthorized(unauthorized) || IsUnauthorized(errors.New("plain error")) {
+		t.Error("IsUnauthorized did not match only 401 errors")
+	}
+	if !IsCanceled(fmt.Errorf("wrapped: %w", context.Canceled)) || !IsCanceled(context.DeadlineExceeded) || IsCanceled(notFound) {
+		t.Error("IsCanceled did not match only context errors")
+	}
+}
@@ -0,0 +1,404 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FixtureMode selects whether requests sent to the appliance are recorded to, or replayed from, a fixture file
+type FixtureMode string
+
+const (
+	// FixtureModeRecord sends requests to the appliance and records every exchange, with secrets scrubbed
+	FixtureModeRecord FixtureMode = "record"
+	// FixtureModeReplay answers requests from a fixture file without contacting the appliance
+	FixtureModeReplay FixtureMode = "replay"
+)
+
+// FixtureConfig describes how requests are recorded or replayed. The zero value sends requests to the
+// appliance without recording them.
+type FixtureConfig struct {
+	Mode FixtureMode
+	// Path of the fixture file. Recording appends to it, since Terraform configures the provider again for
+	// every command of a run. Remove it to record from scratch.
+	Path string
+}
+
+// Fixture is a recorded exchange with the appliance. Secrets in the URL, headers and bodies are replaced
+// with "***" before it is stored.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest is a recorded request. The URL only holds the path and query so that fixtures recorded
+// against one appliance replay against any host.
+type FixtureRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// FixtureResponse is a recorded response
+type FixtureResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// fixtureFile is the document stored at FixtureConfig.Path
+type fixtureFile struct {
+	Fixtures []Fixture `json:"fixtures"`
+}
+
+// ConfigureFixtures records requests sent by every client created from c to a fixture file, or replays
+// them from one. Replaying fails when the file cannot be read.
+func (c *Client) ConfigureFixtures(config FixtureConfig) error {
+	var store *fixtureStore
+	switch config.Mode {
+	case "":
+	case FixtureModeRecord, FixtureModeReplay:
+		if config.Path == "" {
+			return fmt.Errorf("a fixture file is required to %s requests", config.Mode)
+		}
+
+		var err error
+		if store, err = newFixtureStore(config); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid fixture mode %q: must be %q or %q", config.Mode, FixtureModeRecord, FixtureModeReplay)
+	}
+
+	c.fixtures = store
+	c.resetHTTPClients()
+	return nil
+}
+
+// fixtureStore holds the fixtures of a client. It is shared by every pooled HTTP client.
+type fixtureStore struct {
+	mode FixtureMode
+	path string
+
+	mu       sync.Mutex
+	fixtures []Fixture
+	// replayed counts, per request key, the fixtures already served so that repeated requests are answered
+	// in the order they were recorded
+	replayed map[string]int
+}
+
+func newFixtureStore(config FixtureConfig) (*fixtureStore, error) {
+	store := &fixtureStore{mode: config.Mode, path: config.Path, replayed: map[string]int{}}
+
+	data, err := os.ReadFile(config.Path)
+	switch {
+	case err == nil:
+		var file fixtureFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("error parsing fixtures %s: %w", config.Path, err)
+		}
+		store.fixtures = file.Fixtures
+	case config.Mode == FixtureModeReplay || !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("error reading fixtures: %w", err)
+	}
+
+	if config.Mode == FixtureModeRecord {
+		// Fail now rather than after the first request when the file cannot be written
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		if err := store.save(); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// transport returns the round tripper recording to or replaying from the store in place of next
+func (s *fixtureStore) transport(next http.RoundTripper) http.RoundTripper {
+	if s.mode == FixtureModeReplay {
+		return &replayTransport{store: s}
+	}
+	return &recordTransport{next: next, store: s}
+}
+
+// record appends fixture and rewrites the file, so that an interrupted run keeps what was recorded
+func (s *fixtureStore) record(fixture Fixture) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fixtures = append(s.fixtures, fixture)
+	return s.save()
+}
+
+// save writes the fixtures to a temporary file renamed over the fixture file. Callers hold s.mu.
+func (s *fixtureStore) save() error {
+	data, err := json.MarshalIndent(fixtureFile{Fixtures: s.fixtures}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("error writing fixtures: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing fixtures: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing fixtures: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error writing fixtures: %w", err)
+	}
+	return nil
+}
+
+// match returns the next recorded response to the request identified by key. Once every matching fixture
+// was served the last one is served again, so that repeated reads are answered.
+func (s *fixtureStore) match(key string) (*FixtureResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var last *FixtureResponse
+	seen := 0
+	for i := range s.fixtures {
+		if fixtureKey(s.fixtures[i].Request) != key {
+			continue
+		}
+		last = &s.fixtures[i].Response
+		if seen == s.replayed[key] {
+			s.replayed[key]++
+			return last, true
+		}
+		seen++
+	}
+
+	return last, last != nil
+}
+
+// fixtureKey identifies a scrubbed request. Headers are not part of it since the access token they carry
+// differs on every run.
+func fixtureKey(req FixtureRequest) string {
+	return req.Method + " " + req.URL + "\n" + req.Body
+}
+
+// recordTransport sends requests to the appliance and records each exchange
+type recordTransport struct {
+	next  http.RoundTripper
+	store *fixtureStore
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fixtureReq, err := newFixtureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	// Buffer the body so that it can be recorded and still be read by the caller
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture := Fixture{
+		Request: fixtureReq,
+		Response: FixtureResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    scrubResponseHeaders(resp.Header),
+			Body:       scrubBody(resp.Header.Get("Content-Type"), body),
+		},
+	}
+	if err := t.store.record(fixture); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (t *recordTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
+
+// replayTransport answers requests from the fixtures without contacting the appliance
+type replayTransport struct {
+	store *fixtureStore
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fixtureReq, err := newFixtureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, ok := t.store.match(fixtureKey(fixtureReq))
+	if !ok {
+		return nil, fmt.Errorf("no fixture recorded in %s for %s %s", t.store.path, fixtureReq.Method, fixtureReq.URL)
+	}
+
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// newFixtureRequest returns the scrubbed form of req. The request body is read through GetBody so that it
+// can still be sent.
+func newFixtureRequest(req *http.Request) (FixtureRequest, error) {
+	fixtureReq := FixtureRequest{
+		Method:  req.Method,
+		URL:     scrubURL(req.URL),
+		Headers: redactHeaders(req.Header),
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	if req.Body == nil || req.Body == http.NoBody {
+		return fixtureReq, nil
+	}
+	if !isTextBody(contentType) {
+		// Uploads are summarized, they would bloat fixtures and may hold sensitive data
+		fixtureReq.Body = binaryBodySummary(contentType)
+		return fixtureReq, nil
+	}
+
+	if req.GetBody == nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return fixtureReq, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return fixtureReq, err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fixtureReq, err
+	}
+	fixtureReq.Body = scrubBody(contentType, data)
+	return fixtureReq, nil
+}
+
+// scrubURL returns the path and query of u with the values of secret query parameters masked
+func scrubURL(u *url.URL) string {
+	scrubbed := url.URL{Path: u.Path}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if isSecretKey(key) {
+				query[key] = []string{redacted}
+			}
+		}
+		scrubbed.RawQuery = query.Encode()
+	}
+
+	return scrubbed.String()
+}
+
+// scrubResponseHeaders returns the response headers without cookies or length and with secret values masked
+func scrubResponseHeaders(header http.Header) http.Header {
+	scrubbed := make(http.Header, len(header))
+	for name, values := range header {
+		switch {
+		case strings.EqualFold(name, "Set-Cookie"), strings.EqualFold(name, "Content-Length"):
+			// Scrubbing changes the length of the body
+		case isSecretKey(name):
+			scrubbed[name] = []string{redacted}
+		default:
+			scrubbed[name] = values
+		}
+	}
+
+	return scrubbed
+}
+
+// isTextBody reports whether a body of contentType is recorded, rather than summarized
+func isTextBody(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "" || mediaType == "application/x-www-form-urlencoded" || mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") || strings.HasPrefix(mediaType, "text/")
+}
+
+func binaryBodySummary(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return "<" + mediaType + " body>"
+}
+
+// scrubBody returns a body with secret form and JSON fields masked, and bearer tokens and secret JSON
+// fields masked in any other text
+func scrubBody(contentType string, data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if !isTextBody(contentType) {
+		return binaryBodySummary(contentType)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(string(data)); err == nil {
+			for key := range form {
+				if isSecretKey(key) {
+					form[key] = []string{redacted}
+				}
+			}
+			return form.Encode()
+		}
+	}
+
+	// Numbers are kept as they were sent, IDs may not fit a float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err == nil && !decoder.More() {
+		if scrubbed, err := json.Marshal(redactJSON(value)); err == nil {
+			return string(scrubbed)
+		}
+	}
+
+	text := string(data)
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, redacted)
+	}
+	return text
+}
@@ -0,0 +1,136 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newFixtureClient returns a client for server with the given fixture settings and provider credentials
+func newFixtureClient(t *testing.T, server *httptest.Server, config FixtureConfig) API {
+	t.Helper()
+
+	serverURL := strings.TrimPrefix(server.URL, "https://")
+	urlSplit := strings.Split(serverURL, ":")
+	client := NewClient(urlSplit[0], urlSplit[1])
+	client.ConfigureCredentials(Credentials{ClientID: "client1", ClientSecret: "client-secret-value", Username: "admin", Password: "password-value"})
+	if err := client.ConfigureFixtures(config); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	api, err := client.NewSecureClient(writeCertificateAuthority(t, server))
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	return api
+}
+
+func TestFixturesRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	var reads atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=cookie-value")
+		switch {
+		case r.URL.Path == oauthTokenPath:
+			_, _ = w.Write([]byte(`{"access_token":"access-token-value","refresh_token":"refresh-token-value","expires_in":3600}`))
+		case r.Method == http.MethodGet && r.URL.Path == restAPIPath+"aws_secrets_manager":
+			// Every read reports a different ID so that replay order can be checked
+			_, _ = fmt.Fprintf(w, `[{"name":"aws-prod","authType":"Security-Credentials","accessKeyID":"AKIAEXAMPLE","secretAccessKey":"secret-key-value","ID":%d}]`, reads.Add(1))
+		case r.Method == http.MethodPost && r.URL.Path == restAPIPath+"datasource":
+			_, _ = w.Write([]byte(`{"ID":20000000000000001,"Message":"Datasource registered"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	recorder := newFixtureClient(t, server, FixtureConfig{Mode: FixtureModeRecord, Path: path})
+	if err := recorder.RegisterVADataSource(ctx, "", []byte(`{"datasourceName":"db2-prod","password":"db-password-value"}`)); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	for range 2 {
+		if _, err := recorder.Execute(ctx, "", Command{Name: "aws_secrets_manager"}); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected fixtures to be written, got %v", err)
+	}
+	for _, secret := range []string{"client-secret-value", "password-value", "access-token-value", "refresh-token-value", "secret-key-value", "db-password-value", "cookie-value"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q to be scrubbed from the fixtures:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), "20000000000000001") {
+		t.Errorf("Expected IDs to be recorded as sent, got:\n%s", data)
+	}
+
+	// The server is closed, every response comes from the fixtures
+	replayer := newFixtureClient(t, server, FixtureConfig{Mode: FixtureModeReplay, Path: path})
+	if err := replayer.RegisterVADataSource(ctx, "", []byte(`{"datasourceName":"db2-prod","password":"another-password"}`)); err != nil {
+		t.Fatalf("Expected the registration to be replayed, got %v", err)
+	}
+	// Repeated reads are replayed in the order they were recorded, then the last one is repeated
+	for i, id := range []int{1, 2, 2} {
+		resp, err := replayer.Execute(ctx, "", Command{Name: "aws_secrets_manager"})
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if !strings.Contains(string(resp.Body), fmt.Sprintf(`"ID":%d`, id)) {
+			t.Errorf("Expected read %d to replay ID %d, got %s", i+1, id, resp.Body)
+		}
+	}
+
+	// Recording again appends to the fixtures
+	recorder = newFixtureClient(t, server, FixtureConfig{Mode: FixtureModeRecord, Path: path})
+	if store := recorder.(*SecureClient).Client.fixtures; len(store.fixtures) != 4 {
+		t.Errorf("Expected the 4 recorded fixtures to be kept, got %d", len(store.fixtures))
+	}
+
+	_, err = replayer.Execute(ctx, "", Command{Name: "va/config", Params: map[string]string{"datasourceName": "db2-prod"}})
+	if err == nil || !strings.Contains(err.Error(), "no fixture recorded") {
+		t.Errorf("Expected a request that was not recorded to fail, got %v", err)
+	}
+}
+
+func TestConfigureFixtures(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        FixtureConfig
+		expectedError string
+	}{
+		{name: "Disabled", config: FixtureConfig{}},
+		{name: "Invalid mode", config: FixtureConfig{Mode: "capture", Path: "fixtures.json"}, expectedError: "invalid fixture mode"},
+		{name: "Missing file", config: FixtureConfig{Mode: FixtureModeRecord}, expectedError: "fixture file is required"},
+		{name: "Unwritable file", config: FixtureConfig{Mode: FixtureModeRecord, Path: filepath.Join(t.TempDir(), "missing", "fixtures.json")}, expectedError: "error writing fixtures"},
+		{name: "Missing fixtures", config: FixtureConfig{Mode: FixtureModeReplay, Path: filepath.Join(t.TempDir(), "fixtures.json")}, expectedError: "error reading fixtures"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewClient("localhost", "8443").ConfigureFixtures(tc.config)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
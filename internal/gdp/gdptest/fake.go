@@ -0,0 +1,399 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gdptest provides an in-memory implementation of gdp.API and a mock Guardium Data Protection server
+// for tests and local development.
+package gdptest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// ImportedProfiles records a call to ImportProfilesFromFile
+type ImportedProfiles struct {
+	PathToFile string
+	UpdateMode bool
+}
+
+// InstalledConnector records a call to BulkInstallConnector
+type InstalledConnector struct {
+	UDCName   string
+	GdpMuHost string
+}
+
+// Fake is an in-memory gdp.API. Operations record their effects in the exported fields, which tests may also
+// set up beforehand, and fail with the error set in Errors under the operation name, e.g.
+// "BulkInstallConnector". Access tokens are only checked to be present, either passed explicitly or obtained
+// with the provider credentials when Credentials is set. A Fake is safe for concurrent use.
+type Fake struct {
+	mu sync.Mutex
+
+	// HostName is the appliance host name returned by Host, defaults to "guardium.example.com"
+	HostName string
+	// Credentials makes calls without an access token succeed, as if the provider credentials were configured
+	Credentials bool
+	// AccessToken is returned by GenerateAccessToken, defaults to "fake-access-token"
+	AccessToken string
+	// Version is returned by ApplianceVersion, which fails with gdp.ErrVersionUnknown when it is nil
+	Version *gdp.ApplianceVersion
+	// Role is reported by Health, defaults to gdp.RoleCollector
+	Role gdp.ApplianceRole
+	// ManagedUnits lists the units ValidateTargetUnit accepts
+	ManagedUnits []string
+	// Errors fails the operation named by the key with the error
+	Errors map[string]error
+	// ExecuteFunc answers Execute, which otherwise returns an empty 200 response
+	ExecuteFunc func(ctx context.Context, cmd gdp.Command) (*gdp.Response, error)
+
+	ImportedProfiles    []ImportedProfiles
+	InstalledConnectors []InstalledConnector
+	VADataSources       []json.RawMessage
+	VAConfigs           []json.RawMessage
+	VANotifications     []json.RawMessage
+	AWSSecretsManagers  map[string]gdp.AWSSecretsManagerConfig
+	Commands            []gdp.Command
+	// CAPaths lists the certificate authority bundles passed to WithCAPath
+	CAPaths []string
+}
+
+var _ gdp.API = &Fake{}
+
+// NewFake returns a Fake behaving as a provider configured with credentials
+func NewFake() *Fake {
+	return &Fake{Credentials: true}
+}
+
+// SetError makes the operation named op fail with err, or succeed again when err is nil
+func (f *Fake) SetError(op string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Errors == nil {
+		f.Errors = make(map[string]error)
+	}
+	if err == nil {
+		delete(f.Errors, op)
+		return
+	}
+	f.Errors[op] = err
+}
+
+// check returns the error the operation op fails with. Callers must hold mu.
+func (f *Fake) check(ctx context.Context, op, accessToken string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := f.Errors[op]; err != nil {
+		return err
+	}
+	if accessToken == "" && !f.Credentials {
+		return fmt.Errorf("no access token available: set access_token or configure credentials on the provider")
+	}
+	return nil
+}
+
+func (f *Fake) Execute(ctx context.Context, accessToken string, cmd gdp.Command) (*gdp.Response, error) {
+	f.mu.Lock()
+	err := f.check(ctx, "Execute", accessToken)
+	if err == nil {
+		f.Commands = append(f.Commands, cmd)
+	}
+	executeFunc := f.ExecuteFunc
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if executeFunc != nil {
+		return executeFunc(ctx, cmd)
+	}
+	return &gdp.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil
+}
+
+func (f *Fake) ImportProfilesFromFile(ctx context.Context, accessToken, pathToFile string, updateMode bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "ImportProfilesFromFile", accessToken); err != nil {
+		return err
+	}
+
+	f.ImportedProfiles = append(f.ImportedProfiles, ImportedProfiles{PathToFile: pathToFile, UpdateMode: updateMode})
+	return nil
+}
+
+func (f *Fake) GenerateAccessToken(ctx context.Context, credentials gdp.Credentials) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "GenerateAccessToken", "credentials"); err != nil {
+		return "", err
+	}
+	if err := credentials.Validate(); err != nil {
+		return "", err
+	}
+
+	if f.AccessToken == "" {
+		return "fake-access-token", nil
+	}
+	return f.AccessToken, nil
+}
+
+func (f *Fake) BulkInstallConnector(ctx context.Context, accessToken, udcName, gdpMuHost string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "BulkInstallConnector", accessToken); err != nil {
+		return err
+	}
+
+	f.InstalledConnectors = append(f.InstalledConnectors, InstalledConnector{UDCName: udcName, GdpMuHost: gdpMuHost})
+	return nil
+}
+
+func (f *Fake) CreateAWSSecretsManager(ctx context.Context, accessToken string, config *gdp.AWSSecretsManagerConfig) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "CreateAWSSecretsManager", accessToken); err != nil {
+		return err
+	}
+
+	if _, ok := f.AWSSecretsManagers[config.Name]; ok {
+		return &gdp.APIError{StatusCode: http.StatusConflict, Endpoint: "POST /restAPI/aws_secrets_manager", Message: fmt.Sprintf("%s already exists", config.Name)}
+	}
+	if f.AWSSecretsManagers == nil {
+		f.AWSSecretsManagers = make(map[string]gdp.AWSSecretsManagerConfig)
+	}
+	f.AWSSecretsManagers[config.Name] = *config
+	return nil
+}
+
+func (f *Fake) GetAWSSecretsManager(ctx context.Context, accessToken string, name string) (*gdp.AWSSecretsManagerConfig, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "GetAWSSecretsManager", accessToken); err != nil {
+		return nil, err
+	}
+
+	// Like the appliance, a missing configuration is not an error
+	config, ok := f.AWSSecretsManagers[name]
+	if !ok {
+		return nil, nil
+	}
+	return &config, nil
+}
+
+func (f *Fake) GetExistingAWSSecretsManagerNames(ctx context.Context, accessToken string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "GetExistingAWSSecretsManagerNames", accessToken); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range f.AWSSecretsManagers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *Fake) UpdateAWSSecretsManager(ctx context.Context, accessToken string, config *gdp.AWSSecretsManagerConfig) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "UpdateAWSSecretsManager", accessToken); err != nil {
+		return err
+	}
+
+	if _, ok := f.AWSSecretsManagers[config.Name]; !ok {
+		return &gdp.APIError{StatusCode: http.StatusNotFound, Endpoint: "PUT /restAPI/aws_secrets_manager", Message: fmt.Sprintf("%s not found", config.Name)}
+	}
+	f.AWSSecretsManagers[config.Name] = *config
+	return nil
+}
+
+func (f *Fake) DeleteAWSSecretsManager(ctx context.Context, accessToken string, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "DeleteAWSSecretsManager", accessToken); err != nil {
+		return err
+	}
+
+	if _, ok := f.AWSSecretsManagers[name]; !ok {
+		return &gdp.APIError{StatusCode: http.StatusNotFound, Endpoint: "DELETE /restAPI/aws_secrets_manager", Message: fmt.Sprintf("%s not found", name)}
+	}
+	delete(f.AWSSecretsManagers, name)
+	return nil
+}
+
+func (f *Fake) RegisterVADataSource(ctx context.Context, accessToken string, payload []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "RegisterVADataSource", accessToken); err != nil {
+		return err
+	}
+
+	if !json.Valid(payload) {
+		return fmt.Errorf("invalid register data source payload: not a JSON document")
+	}
+	f.VADataSources = append(f.VADataSources, json.RawMessage(payload))
+	return nil
+}
+
+func (f *Fake) ConfigureVADataSource(ctx context.Context, accessToken string, payload []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "ConfigureVADataSource", accessToken); err != nil {
+		return err
+	}
+
+	if !json.Valid(payload) {
+		return fmt.Errorf("invalid configure VA data source payload: not a JSON document")
+	}
+	f.VAConfigs = append(f.VAConfigs, json.RawMessage(payload))
+	return nil
+}
+
+func (f *Fake) ConfigureVANotifications(ctx context.Context, accessToken string, payload []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "ConfigureVANotifications", accessToken); err != nil {
+		return err
+	}
+
+	if !json.Valid(payload) {
+		return fmt.Errorf("invalid configure VA notifications payload: not a JSON document")
+	}
+	f.VANotifications = append(f.VANotifications, json.RawMessage(payload))
+	return nil
+}
+
+func (f *Fake) ApplianceVersion(ctx context.Context, accessToken string) (gdp.ApplianceVersion, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "ApplianceVersion", accessToken); err != nil {
+		return gdp.ApplianceVersion{}, err
+	}
+
+	if f.Version == nil {
+		return gdp.ApplianceVersion{}, gdp.ErrVersionUnknown
+	}
+	return *f.Version, nil
+}
+
+// RequireCapability checks capability against Version, supporting every capability when Version is nil
+func (f *Fake) RequireCapability(ctx context.Context, accessToken string, capability gdp.Capability) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "RequireCapability", accessToken); err != nil {
+		return err
+	}
+
+	if f.Version == nil {
+		return nil
+	}
+	return f.Version.Require(capability)
+}
+
+func (f *Fake) ValidateTargetUnit(ctx context.Context, accessToken, unit string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(ctx, "ValidateTargetUnit", accessToken); err != nil {
+		return err
+	}
+
+	for _, u := range f.ManagedUnits {
+		if strings.EqualFold(u, unit) {
+			return nil
+		}
+	}
+	return fmt.Errorf("target unit %q is not registered with the Central Manager %s, registered units are: %s", unit, f.host(), strings.Join(f.ManagedUnits, ", "))
+}
+
+// Health reports a reachable appliance with Role and Version. Errors under "Health" make it unreachable.
+func (f *Fake) Health(ctx context.Context, accessToken string) (*gdp.Health, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	health := &gdp.Health{Endpoint: "https://" + f.host(), Role: gdp.RoleUnknown}
+	if err := ctx.Err(); err != nil {
+		return health, err
+	}
+	if err := f.Errors["Health"]; err != nil {
+		return health, err
+	}
+	health.Reachable = true
+	if accessToken == "" && !f.Credentials {
+		return health, nil
+	}
+
+	health.Authenticated = true
+	if accessToken == "" {
+		health.User = "fake-user"
+	}
+	health.Role = f.Role
+	if health.Role == "" {
+		health.Role = gdp.RoleCollector
+	}
+	if f.Version != nil {
+		version := *f.Version
+		health.Version = &version
+	}
+	return health, nil
+}
+
+// Host returns HostName
+func (f *Fake) Host() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.host()
+}
+
+func (f *Fake) host() string {
+	if f.HostName == "" {
+		return "guardium.example.com"
+	}
+	return f.HostName
+}
+
+// HasCredentials returns Credentials
+func (f *Fake) HasCredentials() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.Credentials
+}
+
+// WithCAPath records caPath and returns f, so that every operation is recorded in the same place
+func (f *Fake) WithCAPath(caPath string) (gdp.API, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors["WithCAPath"]; err != nil {
+		return nil, err
+	}
+	if caPath != "" {
+		f.CAPaths = append(f.CAPaths, caPath)
+	}
+	return f, nil
+}
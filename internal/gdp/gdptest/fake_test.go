@@ -0,0 +1,100 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdptest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+
+	config := gdp.NewAWSSecretsManagerConfig("aws", "Security-Credentials", "id", "secret", "user", "password")
+	if err := fake.CreateAWSSecretsManager(ctx, "", config); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := fake.CreateAWSSecretsManager(ctx, "", config); !gdp.IsConflict(err) {
+		t.Errorf("Expected a conflict creating the configuration twice, got %v", err)
+	}
+	if err := fake.DeleteAWSSecretsManager(ctx, "", "missing"); !gdp.IsNotFound(err) {
+		t.Errorf("Expected deleting a missing configuration to be not found, got %v", err)
+	}
+
+	failure := errors.New("appliance unavailable")
+	fake.SetError("BulkInstallConnector", failure)
+	if err := fake.BulkInstallConnector(ctx, "", "udc", "mu"); !errors.Is(err, failure) {
+		t.Errorf("Expected the injected error, got %v", err)
+	}
+	fake.SetError("BulkInstallConnector", nil)
+	if err := fake.BulkInstallConnector(ctx, "", "udc", "mu"); err != nil || len(fake.InstalledConnectors) != 1 {
+		t.Errorf("Expected the connector to be installed once, got %v and %+v", err, fake.InstalledConnectors)
+	}
+
+	if _, err := fake.ApplianceVersion(ctx, ""); !errors.Is(err, gdp.ErrVersionUnknown) {
+		t.Errorf("Expected an unknown version, got %v", err)
+	}
+
+	if health, err := fake.Health(ctx, ""); err != nil || !health.Authenticated || health.Role != gdp.RoleCollector {
+		t.Errorf("Expected an authenticated collector, got %+v and %v", health, err)
+	}
+
+	noCredentials := &Fake{}
+	if err := noCredentials.ImportProfilesFromFile(ctx, "", "profiles.json", true); err == nil {
+		t.Error("Expected calls without an access token to fail without provider credentials")
+	}
+	if err := noCredentials.ImportProfilesFromFile(ctx, "token", "profiles.json", true); err != nil {
+		t.Errorf("Expected no error with an access token, got %v", err)
+	}
+}
+
+func TestFakeNotFound(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+
+	config := gdp.NewAWSSecretsManagerConfig("missing", "Security-Credentials", "id", "secret", "user", "password")
+	if err := fake.UpdateAWSSecretsManager(ctx, "", config); !gdp.IsNotFound(err) {
+		t.Errorf("Expected updating a missing configuration to be not found, got %v", err)
+	}
+	if got, err := fake.GetAWSSecretsManager(ctx, "", "missing"); got != nil || err != nil {
+		t.Errorf("Expected reading a missing configuration to return nothing, got %+v and %v", got, err)
+	}
+	if len(fake.AWSSecretsManagers) != 0 {
+		t.Errorf("Expected no configuration to be stored, got %+v", fake.AWSSecretsManagers)
+	}
+
+	fake.ManagedUnits = []string{"mu1.example.com", "mu2.example.com"}
+	if err := fake.ValidateTargetUnit(ctx, "", "MU1.example.com"); err != nil {
+		t.Errorf("Expected managed units to match regardless of case, got %v", err)
+	}
+	if err := fake.ValidateTargetUnit(ctx, "", "mu3.example.com"); err == nil || !strings.Contains(err.Error(), "mu1.example.com, mu2.example.com") {
+		t.Errorf("Expected an unknown unit to be rejected with the registered units, got %v", err)
+	}
+}
+
+func TestFakeVAPayloads(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+
+	for name, call := range map[string]func(context.Context, string, []byte) error{
+		"RegisterVADataSource":     fake.RegisterVADataSource,
+		"ConfigureVADataSource":    fake.ConfigureVADataSource,
+		"ConfigureVANotifications": fake.ConfigureVANotifications,
+	} {
+		if err := call(ctx, "", []byte("datasourceName=db2")); err == nil {
+			t.Errorf("%s: expected a payload that is not JSON to be rejected", name)
+		}
+		if err := call(ctx, "", []byte(`{"datasourceName":"db2"}`)); err != nil {
+			t.Errorf("%s: expected no error but got: %v", name, err)
+		}
+	}
+	if len(fake.VADataSources) != 1 || len(fake.VAConfigs) != 1 || len(fake.VANotifications) != 1 {
+		t.Errorf("Expected only the valid payloads to be recorded, got %d, %d and %d", len(fake.VADataSources), len(fake.VAConfigs), len(fake.VANotifications))
+	}
+}
@@ -0,0 +1,692 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdptest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// Default credentials accepted by a Server returned by NewServer
+const (
+	DefaultClientID     = "client1"
+	DefaultClientSecret = "mock-secret"
+	DefaultUsername     = "admin"
+	DefaultPassword     = "mock-password"
+)
+
+// maxUploadMemory is how much of a profile upload is held in memory, the rest is buffered on disk
+const maxUploadMemory = 1 << 20
+
+// UploadedProfiles records a call to importProfilesFromFile. PathToFile is the appliance path for the JSON
+// form of the call, or the name of the uploaded file with Content set for the multipart form.
+type UploadedProfiles struct {
+	PathToFile string
+	UpdateMode bool
+	Content    []byte
+}
+
+// awsSecretsManagerItem is an AWS Secrets Manager configuration as listed by the appliance
+type awsSecretsManagerItem struct {
+	ID                          int    `json:"id"`
+	Name                        string `json:"name"`
+	AccessKeyID                 string `json:"accessKeyId"`
+	SecretAccessKey             string `json:"secretAccessKey"`
+	AuthType                    string `json:"authType"`
+	RoleARN                     string `json:"roleARN"`
+	SecretKeyUsernameIdentifier string `json:"secretKeyUsernameIdentifier"`
+	SecretKeyPasswordIdentifier string `json:"secretKeyPasswordIdentifier"`
+	SecretsManager              bool   `json:"secretsManager"`
+}
+
+// Server is a stateful stand-in for the Guardium Data Protection REST API. It issues OAuth tokens and serves
+// the GuardAPI commands the provider uses, keeping what they create in memory so that resources can be
+// created, read back, updated and deleted. Serve it with httptest.NewTLSServer, or http.Server for local
+// development. The exported fields may be changed before serving; Server is safe for concurrent use.
+type Server struct {
+	mu sync.Mutex
+
+	// ClientID and ClientSecret are the OAuth client accepted by the token endpoint
+	ClientID     string
+	ClientSecret string
+	// Username and Password are the user accepted by the password grant
+	Username string
+	Password string
+	// TokenLifetime is how long issued access tokens are valid
+	TokenLifetime time.Duration
+	// Version and Patch are reported by guardium_version, which is unknown when Version is empty as on
+	// releases older than the command
+	Version string
+	Patch   string
+	// ManagedUnits makes the server a Central Manager managing these units. Commands are only routed to
+	// registered units and bulk installs only accepted on them.
+	ManagedUnits []gdp.ManagedUnit
+	// UnitType is reported by unit_type. When empty the server reports "Manager" when ManagedUnits is set and
+	// "Collector" otherwise.
+	UnitType string
+
+	accessTokens  map[string]time.Time
+	refreshTokens map[string]struct{}
+	commands      map[string]http.Handler
+	nextID        int
+
+	profiles           []UploadedProfiles
+	connectors         []InstalledConnector
+	dataSources        map[string]json.RawMessage
+	vaConfigs          map[string]json.RawMessage
+	vaNotifications    map[string]json.RawMessage
+	awsSecretsManagers map[string]awsSecretsManagerItem
+}
+
+var _ http.Handler = &Server{}
+
+// NewServer returns a Server accepting the default credentials and reporting Guardium 12.1 patch 100
+func NewServer() *Server {
+	return &Server{
+		ClientID:      DefaultClientID,
+		ClientSecret:  DefaultClientSecret,
+		Username:      DefaultUsername,
+		Password:      DefaultPassword,
+		TokenLifetime: time.Hour,
+		Version:       "12.1",
+		Patch:         "100",
+	}
+}
+
+// HandleCommand serves the GuardAPI command name with h, for commands the server does not implement itself or
+// to override one it does. Requests reach h unread once their access token is checked.
+func (s *Server) HandleCommand(name string, h http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.commands == nil {
+		s.commands = make(map[string]http.Handler)
+	}
+	s.commands[name] = h
+}
+
+// ServeHTTP serves the token endpoint and the GuardAPI commands
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/oauth/token" {
+		s.serveToken(w, r)
+		return
+	}
+
+	command, ok := strings.CutPrefix(r.URL.Path, "/restAPI/")
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", r.URL.Path))
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token", "error_description": "Invalid access token"})
+		return
+	}
+
+	s.mu.Lock()
+	h, ok := s.commands[command]
+	s.mu.Unlock()
+	if ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	params, err := readParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if unit, ok := params["api_target_host"].(string); ok {
+		if !s.isManagedUnit(unit) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("api_target_host %s is not a managed unit", unit))
+			return
+		}
+		delete(params, "api_target_host")
+	}
+
+	switch command {
+	case "importProfilesFromFile":
+		s.serveImportProfiles(w, r, params)
+	case "bulkInstall":
+		s.serveBulkInstall(w, r, params)
+	case "datasource":
+		s.serveDataSource(w, r, params)
+	case "va/config":
+		s.serveKeyed(w, r, params, &s.vaConfigs, "vulnerability assessment configuration")
+	case "notifications":
+		s.serveKeyed(w, r, params, &s.vaNotifications, "notification configuration")
+	case "aws_secrets_manager":
+		s.serveAWSSecretsManager(w, r, params)
+	case "guardium_version":
+		s.serveVersion(w, r)
+	case "managed_units":
+		s.serveManagedUnits(w, r)
+	case "unit_type":
+		s.serveUnitType(w, r)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown command %s", command))
+	}
+}
+
+// serveToken implements the password, client_credentials and refresh_token grants
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed", r.Method))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+
+	clientID, clientSecret := r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	if user, password, ok := r.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(user)
+		clientSecret, _ = url.QueryUnescape(password)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	grantType := r.PostForm.Get("grant_type")
+	if clientID != s.ClientID || (clientSecret != s.ClientSecret && (grantType != gdp.GrantTypeRefreshToken || clientSecret != "")) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client", "error_description": "Bad client credentials"})
+		return
+	}
+
+	switch grantType {
+	case gdp.GrantTypePassword:
+		if r.PostForm.Get("username") != s.Username || r.PostForm.Get("password") != s.Password {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "Bad credentials"})
+			return
+		}
+	case gdp.GrantTypeClientCredentials:
+	case gdp.GrantTypeRefreshToken:
+		refreshToken := r.PostForm.Get("refresh_token")
+		if _, ok := s.refreshTokens[refreshToken]; !ok {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "Invalid refresh token"})
+			return
+		}
+		// Refresh tokens are single use, a new one is issued with the access token
+		delete(s.refreshTokens, refreshToken)
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type", "error_description": fmt.Sprintf("Unsupported grant type: %s", grantType)})
+		return
+	}
+
+	if s.accessTokens == nil {
+		s.accessTokens = make(map[string]time.Time)
+		s.refreshTokens = make(map[string]struct{})
+	}
+	accessToken, refreshToken := newToken(), newToken()
+	s.accessTokens[accessToken] = time.Now().Add(s.TokenLifetime)
+	s.refreshTokens[refreshToken] = struct{}{}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "bearer",
+		"expires_in":    int64(s.TokenLifetime / time.Second),
+		"scope":         "read write",
+	})
+}
+
+// authorized reports whether r carries an access token issued by s that has not expired
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiry, ok := s.accessTokens[token]
+	return ok && time.Now().Before(expiry)
+}
+
+// ExpireTokens invalidates every access token issued so far, as when the appliance restarts. Refresh tokens
+// stay valid.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.accessTokens)
+}
+
+func (s *Server) isManagedUnit(unit string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.ManagedUnits {
+		if strings.EqualFold(u.Name, unit) || strings.EqualFold(u.Host, unit) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveImportProfiles(w http.ResponseWriter, r *http.Request, params map[string]any) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed", r.Method))
+		return
+	}
+
+	var profiles UploadedProfiles
+	if r.MultipartForm != nil {
+		file, header, err := r.FormFile("path")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Missing profile file")
+			return
+		}
+		defer file.Close()
+
+		if profiles.Content, err = io.ReadAll(file); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		profiles.PathToFile = header.Filename
+		profiles.UpdateMode, _ = strconv.ParseBool(r.FormValue("updateMode"))
+	} else {
+		profiles.PathToFile, _ = params["path"].(string)
+		profiles.UpdateMode, _ = params["updateMode"].(bool)
+		if profiles.PathToFile == "" {
+			writeError(w, http.StatusBadRequest, "Missing path")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.profiles = append(s.profiles, profiles)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]string{"ID": "0", "Message": fmt.Sprintf("Profiles imported from %s", profiles.PathToFile)})
+}
+
+func (s *Server) serveBulkInstall(w http.ResponseWriter, r *http.Request, params map[string]any) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed", r.Method))
+		return
+	}
+
+	profileNames, _ := params["profileNames"].(string)
+	hosts, _ := params["hosts"].(string)
+	if profileNames == "" || hosts == "" {
+		writeError(w, http.StatusBadRequest, "profileNames and hosts are required")
+		return
+	}
+
+	// Like the appliance, unknown hosts are reported in the message of a successful response
+	for _, host := range strings.Split(hosts, ",") {
+		if len(s.managedUnits()) > 0 && !s.isManagedUnit(strings.TrimSpace(host)) {
+			writeJSON(w, http.StatusOK, map[string]string{"ID": "0", "Message": "One or more of the specified hosts could not be found"})
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.connectors = append(s.connectors, InstalledConnector{UDCName: profileNames, GdpMuHost: hosts})
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]string{"ID": "0", "Message": "Bulk install started"})
+}
+
+// serveDataSource registers datasources, keyed by their name, and lists them
+func (s *Server) serveDataSource(w http.ResponseWriter, r *http.Request, params map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		writePage(w, params, sortedValues(s.dataSources))
+	case http.MethodPost, http.MethodPut:
+		name := dataSourceName(params)
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "Missing datasource name")
+			return
+		}
+
+		// Registering a datasource again updates it in place
+		id, ok := s.dataSourceID(name)
+		if !ok {
+			s.nextID++
+			id = strconv.Itoa(s.nextID)
+		}
+		params["id"] = id
+
+		if s.dataSources == nil {
+			s.dataSources = make(map[string]json.RawMessage)
+		}
+		s.dataSources[name] = mustMarshal(params)
+		writeJSON(w, http.StatusOK, map[string]string{"id": id, "message": fmt.Sprintf("Datasource %s registered", name)})
+	case http.MethodDelete:
+		name := dataSourceName(params)
+		if _, ok := s.dataSources[name]; !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Datasource %s not found", name))
+			return
+		}
+		delete(s.dataSources, name)
+		writeJSON(w, http.StatusOK, map[string]string{"message": fmt.Sprintf("Datasource %s deleted", name)})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed", r.Method))
+	}
+}
+
+func (s *Server) dataSourceID(name string) (string, bool) {
+	var registered struct {
+		ID string `json:"id"`
+	}
+	raw, ok := s.dataSources[name]
+	if !ok {
+		return "", false
+	}
+	_ = json.Unmarshal(raw, &registered)
+	return registered.ID, true
+}
+
+// serveKeyed stores the datasource settings of a command such as va/config in configs, keyed by datasource
+// name, and reads them back
+func (s *Server) serveKeyed(w http.ResponseWriter, r *http.Request, params map[string]any, configs *map[string]json.RawMessage, what string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := dataSourceName(params)
+	switch r.Method {
+	case http.MethodGet:
+		if name == "" {
+			writePage(w, params, sortedValues(*configs))
+			return
+		}
+		config, ok := (*configs)[name]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("No %s for datasource %s", what, name))
+			return
+		}
+		writeJSON(w, http.StatusOK, config)
+	case http.MethodPost, http.MethodPut:
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "Missing datasource_name")
+			return
+		}
+		if *configs == nil {
+			*configs = make(map[string]json.RawMessage)
+		}
+		(*configs)[name] = mustMarshal(params)
+		writeJSON(w, http.StatusOK, map[string]string{"id": name, "message": fmt.Sprintf("Saved %s for datasource %s", what, name)})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed", r.Method))
+	}
+}
+
+func (s *Server) serveAWSSecretsManager(w http.ResponseWriter, r *http.Request, params map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name, _ := params["name"].(string)
+	switch r.Method {
+	case http.MethodGet:
+		items := make([]awsSecretsManagerItem, 0, len(s.awsSecretsManagers))
+		for _, item := range s.awsSecretsManagers {
+			items = append(items, item)
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+		writePage(w, params, items)
+	case http.MethodPost, http.MethodPut:
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "Missing name")
+			return
+		}
+		existing, exists := s.awsSecretsManagers[name]
+		if r.Method == http.MethodPost && exists {
+			writeError(w, http.StatusConflict, fmt.Sprintf("AWS Secrets Manager %s already exists", name))
+			return
+		}
+		if r.Method == http.MethodPut && !exists {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("AWS Secrets Manager %s not found", name))
+			return
+		}
+
+		item := awsSecretsManagerItem{ID: existing.ID, Name: name, SecretsManager: true}
+		item.AuthType, _ = params["auth_type"].(string)
+		item.AccessKeyID, _ = params["access_key_id"].(string)
+		item.SecretAccessKey, _ = params["secret_access_key"].(string)
+		item.SecretKeyUsernameIdentifier, _ = params["secret_key_username"].(string)
+		item.SecretKeyPasswordIdentifier, _ = params["secret_key_password"].(string)
+		if !exists {
+			s.nextID++
+			item.ID = s.nextID
+		}
+
+		if s.awsSecretsManagers == nil {
+			s.awsSecretsManagers = make(map[string]awsSecretsManagerItem)
+		}
+		s.awsSecretsManagers[name] = item
+		writeJSON(w, http.StatusOK, map[string]any{"ID": item.ID, "Message": fmt.Sprintf("AWS Secrets Manager %s saved", name)})
+	case http.MethodDelete:
+		if _, ok := s.awsSecretsManagers[name]; !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("AWS Secrets Manager %s not found", name))
+			return
+		}
+		delete(s.awsSecretsManagers, name)
+		writeJSON(w, http.StatusOK, map[string]string{"ID": "0", "Message": fmt.Sprintf("AWS Secrets Manager %s deleted", name)})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed", r.Method))
+	}
+}
+
+func (s *Server) serveVersion(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	version, patch := s.Version, s.Patch
+	s.mu.Unlock()
+
+	if version == "" {
+		writeError(w, http.StatusNotFound, "Unknown command guardium_version")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"version": version, "patch": patch})
+}
+
+func (s *Server) serveManagedUnits(w http.ResponseWriter, r *http.Request) {
+	units := s.managedUnits()
+	if units == nil {
+		writeError(w, http.StatusNotFound, "Unknown command managed_units")
+		return
+	}
+	writeJSON(w, http.StatusOK, units)
+}
+
+func (s *Server) serveUnitType(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	unitType := s.UnitType
+	if unitType == "" {
+		unitType = "Collector"
+		if s.ManagedUnits != nil {
+			unitType = "Manager"
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]string{"unit_type": unitType})
+}
+
+func (s *Server) managedUnits() []gdp.ManagedUnit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ManagedUnits
+}
+
+// UploadedProfiles returns the profiles imported so far
+func (s *Server) UploadedProfiles() []UploadedProfiles {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]UploadedProfiles(nil), s.profiles...)
+}
+
+// InstalledConnectors returns the connectors installed so far
+func (s *Server) InstalledConnectors() []InstalledConnector {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]InstalledConnector(nil), s.connectors...)
+}
+
+// DataSource returns the registered datasource name, or nil when there is none
+func (s *Server) DataSource(name string) json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dataSources[name]
+}
+
+// VAConfig returns the vulnerability assessment configuration of the datasource name, or nil when there is none
+func (s *Server) VAConfig(name string) json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.vaConfigs[name]
+}
+
+// VANotifications returns the notification configuration of the datasource name, or nil when there is none
+func (s *Server) VANotifications(name string) json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.vaNotifications[name]
+}
+
+// AWSSecretsManager returns the AWS Secrets Manager configuration name, or nil when there is none
+func (s *Server) AWSSecretsManager(name string) *gdp.AWSSecretsManagerConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.awsSecretsManagers[name]
+	if !ok {
+		return nil
+	}
+	return gdp.NewAWSSecretsManagerConfig(item.Name, item.AuthType, item.AccessKeyID, item.SecretAccessKey, item.SecretKeyUsernameIdentifier, item.SecretKeyPasswordIdentifier)
+}
+
+// readParams returns the parameters of a GuardAPI request: the query parameters merged with the fields of a
+// JSON object body. Multipart forms are parsed into r.MultipartForm instead.
+func readParams(r *http.Request) (map[string]any, error) {
+	params := make(map[string]any)
+	for key := range r.URL.Query() {
+		params[key] = r.URL.Query().Get(key)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+			return nil, fmt.Errorf("invalid multipart form: %w", err)
+		}
+		return params, nil
+	case "application/json":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			return params, nil
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %w", err)
+		}
+		for key, value := range fields {
+			params[key] = value
+		}
+	}
+
+	return params, nil
+}
+
+// dataSourceName returns the datasource a request refers to, which commands name differently
+func dataSourceName(params map[string]any) string {
+	for _, key := range []string{"datasource_name", "datasourceName", "name"} {
+		if name, ok := params[key].(string); ok && name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// writePage lists the page of items selected by the offset and limit parameters, or every item when they
+// are not set
+func writePage[T any](w http.ResponseWriter, params map[string]any, items []T) {
+	bounds := map[string]int{"offset": 0, "limit": len(items)}
+	for key := range bounds {
+		value, ok := params[key].(string)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid %s %q", key, value))
+			return
+		}
+		bounds[key] = n
+	}
+
+	start := min(bounds["offset"], len(items))
+	end := min(start+bounds["limit"], len(items))
+	writeJSON(w, http.StatusOK, items[start:end])
+}
+
+// sortedValues returns the values of m ordered by key, so that listings are stable
+func sortedValues(m map[string]json.RawMessage) []json.RawMessage {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]json.RawMessage, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, m[key])
+	}
+	return values
+}
+
+func mustMarshal(v any) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError reports an error the way GuardAPI does, which the client returns as *gdp.APIError
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"ErrorCode": status, "ErrorMessage": message})
+}
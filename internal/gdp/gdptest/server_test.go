@@ -0,0 +1,301 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdptest
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// newServerClient starts server and returns a client trusting its certificate, configured with credentials
+func newServerClient(t *testing.T, server *Server, credentials *gdp.Credentials) gdp.API {
+	t.Helper()
+
+	ts := httptest.NewTLSServer(server)
+	t.Cleanup(ts.Close)
+
+	u, _ := url.Parse(ts.URL)
+	host, port, _ := net.SplitHostPort(u.Host)
+	client := gdp.NewClient(host, port)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := client.ConfigureTLS(gdp.TLSConfig{CAPEM: string(caPEM)}); err != nil {
+		t.Fatal(err)
+	}
+	if credentials != nil {
+		client.ConfigureCredentials(*credentials)
+	}
+
+	api, err := client.NewSecureClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func defaultCredentials() *gdp.Credentials {
+	return &gdp.Credentials{ClientID: DefaultClientID, ClientSecret: DefaultClientSecret, Username: DefaultUsername, Password: DefaultPassword}
+}
+
+func TestServerToken(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		name        string
+		credentials gdp.Credentials
+		expectError bool
+	}{
+		{name: "Password grant", credentials: *defaultCredentials()},
+		{name: "Client credentials grant", credentials: gdp.Credentials{GrantType: gdp.GrantTypeClientCredentials, ClientID: DefaultClientID, ClientSecret: DefaultClientSecret}},
+		{name: "Basic client authentication", credentials: gdp.Credentials{ClientID: DefaultClientID, ClientSecret: DefaultClientSecret, Username: DefaultUsername, Password: DefaultPassword, ClientAuth: gdp.ClientAuthBasic}},
+		{name: "Wrong password", credentials: gdp.Credentials{ClientID: DefaultClientID, ClientSecret: DefaultClientSecret, Username: DefaultUsername, Password: "wrong"}, expectError: true},
+		{name: "Wrong client secret", credentials: gdp.Credentials{GrantType: gdp.GrantTypeClientCredentials, ClientID: DefaultClientID, ClientSecret: "wrong"}, expectError: true},
+		{name: "Unknown refresh token", credentials: gdp.Credentials{GrantType: gdp.GrantTypeRefreshToken, ClientID: DefaultClientID, RefreshToken: "unknown"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newServerClient(t, NewServer(), nil)
+			token, err := api.GenerateAccessToken(ctx, tc.credentials)
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("Expected an error but got none")
+				}
+				return
+			}
+			if err != nil || token == "" {
+				t.Fatalf("Expected an access token, got %q and %v", token, err)
+			}
+
+			// Only issued tokens are accepted
+			if _, err := api.GetExistingAWSSecretsManagerNames(ctx, token); err != nil {
+				t.Errorf("Expected the issued token to be accepted, got %v", err)
+			}
+			if _, err := api.GetExistingAWSSecretsManagerNames(ctx, "forged"); !gdp.IsUnauthorized(err) {
+				t.Errorf("Expected a forged token to be rejected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestServerAWSSecretsManager(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer()
+	api := newServerClient(t, server, defaultCredentials())
+
+	config := gdp.NewAWSSecretsManagerConfig("aws-prod", "Security-Credentials", "AKIAEXAMPLE", "secret", "username", "password")
+	if err := api.CreateAWSSecretsManager(ctx, "", config); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := api.CreateAWSSecretsManager(ctx, "", config); !gdp.IsConflict(err) {
+		t.Errorf("Expected a conflict creating the configuration twice, got %v", err)
+	}
+
+	got, err := api.GetAWSSecretsManager(ctx, "", "aws-prod")
+	if err != nil || got == nil || *got != *config {
+		t.Fatalf("Expected %+v to be read back, got %+v and %v", config, got, err)
+	}
+
+	config.AuthType = "IAM-Role"
+	if err := api.UpdateAWSSecretsManager(ctx, "", config); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if got := server.AWSSecretsManager("aws-prod"); got == nil || got.AuthType != "IAM-Role" {
+		t.Errorf("Expected the configuration to be updated, got %+v", got)
+	}
+
+	if err := api.DeleteAWSSecretsManager(ctx, "", "aws-prod"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := api.DeleteAWSSecretsManager(ctx, "", "aws-prod"); !gdp.IsNotFound(err) {
+		t.Errorf("Expected deleting a missing configuration to be not found, got %v", err)
+	}
+	if err := api.UpdateAWSSecretsManager(ctx, "", config); !gdp.IsNotFound(err) {
+		t.Errorf("Expected updating a missing configuration to be not found, got %v", err)
+	}
+}
+
+func TestServerVulnerabilityAssessment(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer()
+	api := newServerClient(t, server, defaultCredentials())
+
+	if err := api.RegisterVADataSource(ctx, "", []byte(`{"datasourceName":"db2-prod","datasourceType":"DB2"}`)); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if server.DataSource("db2-prod") == nil {
+		t.Error("Expected the datasource to be registered")
+	}
+
+	config, _ := json.Marshal(gdp.ConfigureDatasourcePayload{DatasourceName: "db2-prod", Schedule: gdp.VASchedule{Frequency: "weekly", Day: "Monday", Time: "23:00"}, Enabled: true})
+	if err := api.ConfigureVADataSource(ctx, "", config); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// Configurations are read back the way the rest_call data source does
+	resp, err := api.Execute(ctx, "", gdp.Command{Name: "va/config", Params: map[string]string{"datasourceName": "db2-prod"}})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	var read gdp.ConfigureDatasourcePayload
+	if err := json.Unmarshal(resp.Body, &read); err != nil || read.Schedule.Day != "Monday" {
+		t.Errorf("Expected the configuration to be read back, got %s", resp.Body)
+	}
+
+	_, err = api.Execute(ctx, "", gdp.Command{Name: "notifications", Params: map[string]string{"datasourceName": "db2-prod"}})
+	if !gdp.IsNotFound(err) {
+		t.Errorf("Expected missing notifications to be not found, got %v", err)
+	}
+	if err := api.ConfigureVANotifications(ctx, "", []byte(`{"datasource_name":"db2-prod","recipients":["dba@example.com"]}`)); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if server.VANotifications("db2-prod") == nil {
+		t.Error("Expected the notifications to be configured")
+	}
+}
+
+func TestServerProfilesAndConnectors(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer()
+	server.ManagedUnits = []gdp.ManagedUnit{{Name: "mu1", Host: "mu1.example.com"}}
+	api := newServerClient(t, server, defaultCredentials())
+
+	path := filepath.Join(t.TempDir(), "profiles.csv")
+	if err := os.WriteFile(path, []byte("name,type\nudc,Oracle\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.ImportProfilesFromFile(ctx, "", path, true); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := api.ImportProfilesFromFile(ctx, "", "/var/dump/profiles.csv", false); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	profiles := server.UploadedProfiles()
+	if len(profiles) != 2 || profiles[0].PathToFile != "profiles.csv" || !strings.Contains(string(profiles[0].Content), "udc,Oracle") || !profiles[0].UpdateMode {
+		t.Errorf("Expected the uploaded file to be recorded, got %+v", profiles)
+	}
+	if profiles[1].PathToFile != "/var/dump/profiles.csv" || profiles[1].Content != nil {
+		t.Errorf("Expected the appliance path to be recorded, got %+v", profiles[1])
+	}
+
+	if err := api.BulkInstallConnector(ctx, "", "udc", "mu1.example.com"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	var apiErr *gdp.APIError
+	if err := api.BulkInstallConnector(ctx, "", "udc", "unknown.example.com"); !errors.As(err, &apiErr) {
+		t.Errorf("Expected an unknown host to be reported, got %v", err)
+	}
+	if connectors := server.InstalledConnectors(); len(connectors) != 1 {
+		t.Errorf("Expected one connector to be installed, got %+v", connectors)
+	}
+
+	// Commands are only routed to managed units
+	if err := api.ValidateTargetUnit(ctx, "", "mu1"); err != nil {
+		t.Errorf("Expected mu1 to be a managed unit, got %v", err)
+	}
+	if err := api.ValidateTargetUnit(ctx, "", "mu2"); err == nil {
+		t.Error("Expected mu2 not to be a managed unit")
+	}
+}
+
+func TestServerHandleCommand(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer()
+	server.HandleCommand("group", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"ID": "42"})
+	}))
+	api := newServerClient(t, server, defaultCredentials())
+
+	resp, err := api.Execute(ctx, "", gdp.Command{Method: http.MethodPost, Name: "group", Params: map[string]string{"desc": "servers"}})
+	if err != nil || !strings.Contains(string(resp.Body), "42") {
+		t.Errorf("Expected the command to be served by the handler, got %v", err)
+	}
+	if _, err := api.Execute(ctx, "", gdp.Command{Name: "unknown"}); !gdp.IsNotFound(err) {
+		t.Errorf("Expected an unknown command to be not found, got %v", err)
+	}
+
+	version, err := api.ApplianceVersion(ctx, "")
+	if err != nil || version.String() != "12.1 patch 100" {
+		t.Errorf("Expected version 12.1 patch 100, got %s and %v", version, err)
+	}
+}
+
+func TestServerExpireTokens(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer()
+	api := newServerClient(t, server, defaultCredentials())
+
+	if _, err := api.GetExistingAWSSecretsManagerNames(ctx, ""); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	server.ExpireTokens()
+	// The provider token is renewed when the server rejects it
+	if _, err := api.GetExistingAWSSecretsManagerNames(ctx, ""); err != nil {
+		t.Errorf("Expected the provider token to be renewed, got %v", err)
+	}
+}
+
+func TestServerHealth(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer()
+	api := newServerClient(t, server, defaultCredentials())
+
+	health, err := api.Health(ctx, "")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !health.Reachable || !health.Authenticated || health.User != DefaultUsername || health.Role != gdp.RoleCollector {
+		t.Errorf("Expected an authenticated collector, got %+v", health)
+	}
+	if health.TLS == nil || !health.TLS.Verified {
+		t.Errorf("Expected a verified TLS connection, got %+v", health.TLS)
+	}
+
+	server.ManagedUnits = []gdp.ManagedUnit{{Name: "mu1"}}
+	if health, err := api.Health(ctx, ""); err != nil || health.Role != gdp.RoleCentralManager {
+		t.Errorf("Expected a Central Manager, got %+v and %v", health, err)
+	}
+	server.UnitType = "Aggregator"
+	if health, err := api.Health(ctx, ""); err != nil || health.Role != gdp.RoleAggregator {
+		t.Errorf("Expected an aggregator, got %+v and %v", health, err)
+	}
+}
+
+func TestServerPagination(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer()
+	api := newServerClient(t, server, defaultCredentials())
+
+	for _, name := range []string{"db2-a", "db2-b", "db2-c", "db2-d", "db2-e"} {
+		if err := api.RegisterVADataSource(ctx, "", []byte(`{"datasourceName":"`+name+`"}`)); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	var names []string
+	for item, err := range gdp.List[map[string]any](ctx, api, "", gdp.Command{Name: "datasource"}, gdp.PageOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		names = append(names, item["datasourceName"].(string))
+	}
+	if strings.Join(names, ",") != "db2-a,db2-b,db2-c,db2-d,db2-e" {
+		t.Errorf("Expected every datasource to be listed in order, got %v", names)
+	}
+
+	var apiErr *gdp.APIError
+	if _, err := api.Execute(ctx, "", gdp.Command{Name: "datasource", Params: map[string]string{"offset": "-1"}}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected an invalid offset to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// restAPIPath is the prefix of every GuardAPI command
+const restAPIPath = "/restAPI/"
+
+// Command describes a GuardAPI call to /restAPI/<Name>
+type Command struct {
+	// Method is the HTTP method, defaults to GET
+	Method string
+	// Name is the GuardAPI command, e.g. "bulkInstall" or "va/config"
+	Name string
+	// Params are the command parameters. GET and HEAD commands send them as query parameters, every other
+	// method as a JSON body. Params may be url.Values, a map or a struct with json tags; json.RawMessage is
+	// sent as is.
+	Params any
+	// Body is sent instead of Params when set, e.g. for multipart uploads
+	Body io.Reader
+	// ContentType is the content type of Body
+	ContentType string
+	// Idempotent marks a command that can safely be repeated, allowing it to be retried after failures
+	// where the appliance may already have processed it
+	Idempotent bool
+
+	// local runs the command on the appliance the client is connected to even when a target unit is configured
+	local bool
+}
+
+// Response is the successful outcome of a GuardAPI command
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Executor runs GuardAPI commands. It is implemented by InsecureClient and SecureClient.
+type Executor interface {
+	Execute(ctx context.Context, accessToken string, cmd Command) (*Response, error)
+}
+
+var (
+	_ Executor = &InsecureClient{}
+	_ Executor = &SecureClient{}
+)
+
+// Execute runs cmd and decodes the JSON response into T. T may also be []byte, json.RawMessage or
+// string to get the raw response body.
+func Execute[T any](ctx context.Context, e Executor, accessToken string, cmd Command) (T, error) {
+	resp, err := e.Execute(ctx, accessToken, cmd)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return decode[T](resp)
+}
+
+// executeAs runs cmd with httpClient and decodes the JSON response into T
+func executeAs[T any](ctx context.Context, c *Client, httpClient *http.Client, accessToken string, cmd Command) (T, error) {
+	resp, err := c.Execute(ctx, httpClient, accessToken, cmd)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return decode[T](resp)
+}
+
+// Execute runs a GuardAPI command. Errors reported by the appliance, through the HTTP status or the
+// response body, are returned as *APIError.
+func (c *Client) Execute(ctx context.Context, httpClient *http.Client, accessToken string, cmd Command) (*Response, error) {
+	method := cmd.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	commandURL, err := url.Parse(c.commandURL(cmd.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid command %q: %w", cmd.Name, err)
+	}
+
+	// A Central Manager runs the command on the managed unit named by the api_target_host parameter
+	var query url.Values
+	if unit := c.targetUnitFor(ctx); unit != "" && !cmd.local {
+		if err := c.ValidateTargetUnit(ctx, httpClient, accessToken, unit); err != nil {
+			return nil, err
+		}
+		if cmd, query, err = routeToUnit(method, cmd, query, unit); err != nil {
+			return nil, fmt.Errorf("error marshaling %s parameters: %w", cmd.Name, err)
+		}
+	}
+
+	body, contentType := cmd.Body, cmd.ContentType
+	if body == nil && cmd.Params != nil {
+		if method == http.MethodGet || method == http.MethodHead {
+			params, err := queryValues(cmd.Params)
+			if err != nil {
+				return nil, fmt.Errorf("error encoding %s parameters: %w", cmd.Name, err)
+			}
+			if query == nil {
+				query = url.Values{}
+			}
+			for key, values := range params {
+				query[key] = append(query[key], values...)
+			}
+		} else {
+			jsonBody, err := json.Marshal(cmd.Params)
+			if err != nil {
+				return nil, fmt.Errorf("error marshaling %s parameters: %w", cmd.Name, err)
+			}
+			body, contentType = bytes.NewReader(jsonBody), "application/json"
+		}
+	}
+
+	if len(query) > 0 {
+		commandURL.RawQuery = query.Encode()
+	}
+
+	if cmd.Idempotent {
+		ctx = WithIdempotent(ctx)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, commandURL.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	// Streamed bodies announce their size and are reopened when the request is retried
+	if stream, ok := body.(streamBody); ok {
+		req.ContentLength = stream.Len()
+		req.GetBody = stream.Reopen
+	}
+
+	setBearer(req, accessToken)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the response status and body for errors reported by the appliance
+	respBody, err := checkResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody}, nil
+}
+
+// commandURL returns the URL of a GuardAPI command
+func (c *Client) commandURL(name string) string {
+	return fmt.Sprintf("%s://%s:%s%s%s", c.protocol, c.Host, c.port, restAPIPath, strings.TrimPrefix(name, "/"))
+}
+
+// decode unmarshals the response body into T, leaving T empty when there is no body
+func decode[T any](resp *Response) (T, error) {
+	var out T
+
+	switch p := any(&out).(type) {
+	case *[]byte:
+		*p = resp.Body
+		return out, nil
+	case *json.RawMessage:
+		*p = resp.Body
+		return out, nil
+	case *string:
+		*p = string(resp.Body)
+		return out, nil
+	}
+
+	if len(bytes.TrimSpace(resp.Body)) == 0 {
+		return out, nil
+	}
+	if err := json.Unmarshal(resp.Body, &out); err != nil {
+		return out, fmt.Errorf("error parsing response body: %w", err)
+	}
+
+	return out, nil
+}
+
+// queryValues encodes command parameters as query parameters. Structs and maps are converted through
+// their JSON representation so that json tags name the parameters.
+func queryValues(params any) (url.Values, error) {
+	switch p := params.(type) {
+	case url.Values:
+		return p, nil
+	case map[string]string:
+		query := url.Values{}
+		for key, value := range p {
+			query.Set(key, value)
+		}
+		return query, nil
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("parameters must be an object: %w", err)
+	}
+
+	query := url.Values{}
+	for key, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			query.Set(key, v)
+		case json.Number:
+			query.Set(key, v.String())
+		case bool:
+			query.Set(key, fmt.Sprint(v))
+		default:
+			// Nested values are passed as JSON text
+			nested, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			query.Set(key, string(nested))
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newGuardAPITestClient returns a plain HTTP client pointed at the given test server
+func newGuardAPITestClient(server *httptest.Server) *Client {
+	urlSplit := strings.Split(strings.TrimPrefix(server.URL, "http://"), ":")
+	return &Client{Host: urlSplit[0], port: urlSplit[1], protocol: "http"}
+}
+
+func TestExecute(t *testing.T) {
+	type datasource struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+
+	testCases := []struct {
+		name          string
+		cmd           Command
+		expectedPath  string
+		expectedQuery url.Values
+		expectedBody  string
+		expectedType  string
+	}{
+		{
+			name:         "GET without parameters",
+			cmd:          Command{Name: "aws_secrets_manager"},
+			expectedPath: "/restAPI/aws_secrets_manager",
+		},
+		{
+			name:          "GET encodes struct parameters as query",
+			cmd:           Command{Name: "datasource", Params: datasource{Name: "db1", Port: 50000}},
+			expectedPath:  "/restAPI/datasource",
+			expectedQuery: url.Values{"name": {"db1"}, "port": {"50000"}},
+		},
+		{
+			name:          "GET passes url.Values through",
+			cmd:           Command{Name: "/va/config", Params: url.Values{"a": {"1", "2"}}},
+			expectedPath:  "/restAPI/va/config",
+			expectedQuery: url.Values{"a": {"1", "2"}},
+		},
+		{
+			name:         "POST encodes parameters as JSON",
+			cmd:          Command{Method: http.MethodPost, Name: "datasource", Params: datasource{Name: "db1", Port: 1}},
+			expectedPath: "/restAPI/datasource",
+			expectedBody: `{"name":"db1","port":1}`,
+			expectedType: "application/json",
+		},
+		{
+			name:         "POST sends raw JSON as is",
+			cmd:          Command{Method: http.MethodPost, Name: "va/config", Params: json.RawMessage(`{"a":1}`)},
+			expectedPath: "/restAPI/va/config",
+			expectedBody: `{"a":1}`,
+			expectedType: "application/json",
+		},
+		{
+			name:         "Explicit body",
+			cmd:          Command{Method: http.MethodPut, Name: "upload", Body: strings.NewReader("data"), ContentType: "text/plain"},
+			expectedPath: "/restAPI/upload",
+			expectedBody: "data",
+			expectedType: "text/plain",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method := tc.cmd.Method
+				if method == "" {
+					method = http.MethodGet
+				}
+				if r.Method != method {
+					t.Errorf("Expected %s request, got %s", method, r.Method)
+				}
+				if r.URL.Path != tc.expectedPath {
+					t.Errorf("Expected path %s, got %s", tc.expectedPath, r.URL.Path)
+				}
+				if tc.expectedQuery != nil && !reflect.DeepEqual(r.URL.Query(), tc.expectedQuery) {
+					t.Errorf("Expected query %v, got %v", tc.expectedQuery, r.URL.Query())
+				}
+				if r.Header.Get("Authorization") != "Bearer test-token" {
+					t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+				}
+				if r.Header.Get("Content-Type") != tc.expectedType {
+					t.Errorf("Expected Content-Type %q, got %q", tc.expectedType, r.Header.Get("Content-Type"))
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != tc.expectedBody {
+					t.Errorf("Expected body %q, got %q", tc.expectedBody, string(body))
+				}
+				w.Write([]byte(`{"ID":"0","Message":"ok"}`))
+			}))
+			defer server.Close()
+
+			client := newGuardAPITestClient(server)
+			resp, err := client.Execute(context.Background(), server.Client(), "test-token", tc.cmd)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestExecuteDecode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/restAPI/list":
+			w.Write([]byte(`[{"name":"a"},{"name":"b"}]`))
+		case "/restAPI/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/restAPI/failure":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"ErrorCode":"17","ErrorMessage":"no such datasource"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newGuardAPITestClient(server)
+	ctx := context.Background()
+
+	items, err := executeAs[[]struct {
+		Name string `json:"name"`
+	}](ctx, client, server.Client(), "", Command{Name: "list"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(items) != 2 || items[1].Name != "b" {
+		t.Errorf("Unexpected decoded list %+v", items)
+	}
+
+	raw, err := executeAs[string](ctx, client, server.Client(), "", Command{Name: "list"})
+	if err != nil || raw != `[{"name":"a"},{"name":"b"}]` {
+		t.Errorf("Expected raw body, got %q (%v)", raw, err)
+	}
+
+	empty, err := executeAs[map[string]any](ctx, client, server.Client(), "", Command{Name: "empty"})
+	if err != nil || empty != nil {
+		t.Errorf("Expected empty result for an empty body, got %v (%v)", empty, err)
+	}
+
+	_, err = executeAs[map[string]any](ctx, client, server.Client(), "", Command{Name: "failure"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if apiErr.ID != "17" || apiErr.Endpoint != "GET /restAPI/failure" {
+		t.Errorf("Unexpected APIError %+v", apiErr)
+	}
+}
+
+func TestVAPayloads(t *testing.T) {
+	payload := `{"datasourceName":"db1","schedule":"daily"}`
+
+	send := map[string]func(*Client, *http.Client, []byte) error{
+		"datasource": func(c *Client, httpClient *http.Client, payload []byte) error {
+			return c.RegisterVADataSource(context.Background(), httpClient, "", payload)
+		},
+		"va/config": func(c *Client, httpClient *http.Client, payload []byte) error {
+			return c.ConfigureVADataSource(context.Background(), httpClient, "", payload)
+		},
+		"notifications": func(c *Client, httpClient *http.Client, payload []byte) error {
+			return c.ConfigureVANotifications(context.Background(), httpClient, "", payload)
+		},
+	}
+
+	for name, fn := range send {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path != "/restAPI/"+name {
+					t.Errorf("Expected path /restAPI/%s, got %s", name, r.URL.Path)
+				}
+				body, _ := io.ReadAll(r.Body)
+				// The payload is a JSON document and must not be sent as a base64 encoded string
+				if string(body) != payload {
+					t.Errorf("Expected body %s, got %s", payload, string(body))
+				}
+				w.Write([]byte(`{"ID":"0"}`))
+			}))
+			defer server.Close()
+
+			client := newGuardAPITestClient(server)
+			if err := fn(client, server.Client(), []byte(payload)); err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if err := fn(client, server.Client(), []byte("datasourceName=db1")); err == nil {
+				t.Error("Expected an error for a payload that is not JSON")
+			}
+			if requests != 1 {
+				t.Errorf("Expected only the valid payload to be sent, got %d requests", requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// ConfigureHeaders sets headers added to every request sent to the appliance, such as a correlation
+// header required by a gateway. Headers set by the client itself, like Authorization and Content-Type,
+// are never overridden.
+func (c *Client) ConfigureHeaders(headers map[string]string) error {
+	extraHeaders := make(http.Header, len(headers))
+	for name, value := range headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("invalid value for header %q", name)
+		}
+		extraHeaders.Set(name, value)
+	}
+
+	c.headers = extraHeaders
+	c.resetHTTPClients()
+	return nil
+}
+
+// headerTransport adds the configured extra headers to every request
+type headerTransport struct {
+	next    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		if _, ok := req.Header[name]; !ok {
+			req.Header[name] = values
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func (t *headerTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
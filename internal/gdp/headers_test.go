@@ -0,0 +1,56 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfigureHeaders(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Correlation-Id"); got != "terraform" {
+			t.Errorf("Expected X-Correlation-Id terraform on %s, got %q", r.URL.Path, got)
+		}
+
+		if r.URL.Path == oauthTokenPath {
+			_, _ = w.Write([]byte(`{"access_token":"provider-token"}`))
+			return
+		}
+
+		// Headers set by the client are not overridden
+		if got := r.Header.Get("Authorization"); got != "Bearer provider-token" {
+			t.Errorf("Expected provider token, got %q", got)
+		}
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.ConfigureCredentials(Credentials{ClientID: "client1", ClientSecret: "secret", Username: "user", Password: "pass"})
+	err := client.ConfigureHeaders(map[string]string{
+		"x-correlation-id": "terraform",
+		"Authorization":    "Bearer ignored",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if err := client.NewInsecureClient().BulkInstallConnector(context.Background(), "", "connector-profile", "host1.example.com"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+}
+
+func TestConfigureHeadersInvalid(t *testing.T) {
+	client := NewClient("localhost", "8443")
+
+	if err := client.ConfigureHeaders(map[string]string{"Bad Header": "value"}); err == nil {
+		t.Error("Expected error for invalid header name but got nil")
+	}
+	if err := client.ConfigureHeaders(map[string]string{"X-Header": "line\nbreak"}); err == nil {
+		t.Error("Expected error for invalid header value but got nil")
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// unitTypeCommand is the GuardAPI command reporting the type of the appliance, as `show unit type` does
+const unitTypeCommand = "unit_type"
+
+// ApplianceRole is the part an appliance plays in a Guardium deployment
+type ApplianceRole string
+
+const (
+	RoleCollector      ApplianceRole = "collector"
+	RoleAggregator     ApplianceRole = "aggregator"
+	RoleCentralManager ApplianceRole = "central_manager"
+	RoleUnknown        ApplianceRole = "unknown"
+)
+
+// ParseApplianceRole returns the role of an appliance reporting unitType, such as "Manager" or "Collector".
+// A Central Manager also aggregates data, it is reported as a Central Manager.
+func ParseApplianceRole(unitType string) ApplianceRole {
+	unitType = strings.ToLower(unitType)
+	switch {
+	case strings.Contains(unitType, "manager"):
+		return RoleCentralManager
+	case strings.Contains(unitType, "aggregator"):
+		return RoleAggregator
+	case strings.Contains(unitType, "collector"), strings.Contains(unitType, "standalone"):
+		return RoleCollector
+	default:
+		return RoleUnknown
+	}
+}
+
+// TLSDetails describes the TLS connection to the appliance
+type TLSDetails struct {
+	Version     string
+	CipherSuite string
+	// ServerName is the name the appliance certificate was verified against, empty when connecting by IP address
+	ServerName string
+	// Subject, Issuer and NotAfter describe the appliance certificate
+	Subject  string
+	Issuer   string
+	NotAfter time.Time
+	// Verified reports whether the certificate was verified, false when verification is disabled
+	Verified bool
+}
+
+func newTLSDetails(state *tls.ConnectionState) *TLSDetails {
+	details := &TLSDetails{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+		Verified:    len(state.VerifiedChains) > 0,
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		details.Subject = cert.Subject.String()
+		details.Issuer = cert.Issuer.String()
+		details.NotAfter = cert.NotAfter
+	}
+	return details
+}
+
+// Health reports how far the provider gets with an appliance, each field being set once its check passed
+type Health struct {
+	// Endpoint is the URL of the appliance
+	Endpoint string
+	// Reachable reports whether the appliance answered over HTTPS, within Latency
+	Reachable bool
+	Latency   time.Duration
+	// TLS describes the connection, nil when it was not made over TLS such as when fixtures are replayed
+	TLS *TLSDetails
+	// Authenticated reports whether the appliance accepted the access token
+	Authenticated bool
+	// User is the user the provider credentials authenticate as, empty when an explicit access token is used
+	User string
+	// Role is RoleUnknown when the appliance reports neither its unit type nor its managed units
+	Role ApplianceRole
+	// Version is nil when the appliance does not report it
+	Version *ApplianceVersion
+}
+
+// Health checks in turn that the appliance is reachable, that it accepts accessToken or the provider
+// credentials and which role and version it has. The returned Health is never nil and describes the checks
+// that passed, the error describes the first that failed. Without an access token or provider credentials
+// only reachability is checked.
+func (c *Client) Health(ctx context.Context, httpClient *http.Client, accessToken string) (*Health, error) {
+	health := &Health{Endpoint: fmt.Sprintf("%s://%s:%s", c.protocol, c.Host, c.port), Role: RoleUnknown}
+
+	// The probe is sent without an access token so that connection problems are told apart from rejected
+	// credentials, any answer shows the appliance is reachable. It is not retried so that an unreachable
+	// appliance is reported at once.
+	req, err := http.NewRequestWithContext(withoutAuthorization(withoutRetry(ctx)), http.MethodHead, health.Endpoint+"/", nil)
+	if err != nil {
+		return health, err
+	}
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return health, fmt.Errorf("could not reach %s: %w", health.Endpoint, err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	health.Reachable = true
+	health.Latency = time.Since(start)
+	if resp.TLS != nil {
+		health.TLS = newTLSDetails(resp.TLS)
+	}
+
+	if accessToken == "" {
+		if !c.HasCredentials() {
+			return health, nil
+		}
+		if _, err := c.providerToken(ctx, httpClient); err != nil {
+			return health, fmt.Errorf("could not authenticate to %s: %w", health.Endpoint, err)
+		}
+		health.User = c.tokenUser()
+	}
+
+	// The role is read first as it is never cached, so that the access token is always checked
+	role, err := c.applianceRole(ctx, httpClient, accessToken)
+	if IsUnauthorized(err) {
+		return health, fmt.Errorf("could not authenticate to %s: %w", health.Endpoint, err)
+	}
+	if err != nil {
+		return health, err
+	}
+	health.Authenticated = true
+	health.Role = role
+
+	version, err := c.ApplianceVersion(ctx, httpClient, accessToken)
+	if err != nil && !errors.Is(err, ErrVersionUnknown) {
+		return health, err
+	}
+	if err == nil {
+		health.Version = &version
+	}
+
+	tflog.Debug(ctx, "checked appliance health", map[string]any{"endpoint": health.Endpoint, "role": string(health.Role), "latency": health.Latency.String()})
+	return health, nil
+}
+
+// unitTypeResponse is the body of the unit type command
+type unitTypeResponse struct {
+	UnitType string `json:"unit_type"`
+}
+
+// applianceRole returns the role reported by the unit type command. Appliances without the command do not
+// support the probe: they are Central Managers when they list their managed units, and of an unknown role
+// otherwise. Only a rejected access token or an interrupted request then fail.
+func (c *Client) applianceRole(ctx context.Context, httpClient *http.Client, accessToken string) (ApplianceRole, error) {
+	resp, err := executeAs[unitTypeResponse](ctx, c, httpClient, accessToken, Command{Name: unitTypeCommand, Idempotent: true, local: true})
+	if err == nil {
+		return ParseApplianceRole(resp.UnitType), nil
+	}
+	if !IsNotFound(err) {
+		return RoleUnknown, fmt.Errorf("error getting appliance role: %w", err)
+	}
+	tflog.Debug(ctx, "appliance does not report its unit type", map[string]any{"error": err.Error()})
+
+	_, err = c.ManagedUnits(ctx, httpClient, accessToken)
+	switch {
+	case err == nil:
+		return RoleCentralManager, nil
+	case IsUnauthorized(err), IsCanceled(err):
+		return RoleUnknown, fmt.Errorf("error getting appliance role: %w", err)
+	default:
+		tflog.Debug(ctx, "appliance role unknown", map[string]any{"error": err.Error()})
+		return RoleUnknown, nil
+	}
+}
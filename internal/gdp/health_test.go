@@ -0,0 +1,205 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseApplianceRole(t *testing.T) {
+	testCases := map[string]ApplianceRole{
+		"Manager":            RoleCentralManager,
+		"Manager Aggregator": RoleCentralManager,
+		"aggregator":         RoleAggregator,
+		"Collector":          RoleCollector,
+		"Standalone":         RoleCollector,
+		"":                   RoleUnknown,
+	}
+
+	for unitType, expected := range testCases {
+		if got := ParseApplianceRole(unitType); got != expected {
+			t.Errorf("Expected unit type %q to be %s, got %s", unitType, expected, got)
+		}
+	}
+}
+
+// healthServer answers the health checks. Commands missing from commands are unknown to the appliance.
+func healthServer(t *testing.T, tokenStatus int, commands map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			if r.Method != http.MethodHead || r.Header.Get("Authorization") != "" {
+				t.Errorf("Expected an unauthorized HEAD probe, got %s with Authorization %q", r.Method, r.Header.Get("Authorization"))
+			}
+			w.WriteHeader(http.StatusFound)
+		case r.URL.Path == oauthTokenPath:
+			w.WriteHeader(tokenStatus)
+			if tokenStatus == http.StatusOK {
+				_, _ = w.Write([]byte(`{"access_token":"provider-token"}`))
+			} else {
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			}
+		case r.Header.Get("Authorization") == "Bearer rejected-token":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_token"}`))
+		default:
+			body, ok := commands[strings.TrimPrefix(r.URL.Path, restAPIPath)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"ErrorCode":404,"ErrorMessage":"Unknown command"}`))
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHealth(t *testing.T) {
+	version := map[string]string{versionCommand: `{"version":"12.1","patch":"100"}`}
+
+	testCases := []struct {
+		name                  string
+		tokenStatus           int
+		commands              map[string]string
+		credentials           bool
+		accessToken           string
+		expectedAuthenticated bool
+		expectedUser          string
+		expectedRole          ApplianceRole
+		expectedVersion       string
+		expectedError         string
+	}{
+		{
+			name:                  "Collector",
+			commands:              map[string]string{unitTypeCommand: `{"unit_type":"Collector"}`, versionCommand: version[versionCommand]},
+			credentials:           true,
+			expectedAuthenticated: true,
+			expectedUser:          "admin",
+			expectedRole:          RoleCollector,
+			expectedVersion:       "12.1 patch 100",
+		},
+		{
+			name:                  "Central Manager without unit type",
+			commands:              map[string]string{managedUnitsCommand: managedUnitsResponse},
+			accessToken:           "explicit-token",
+			expectedAuthenticated: true,
+			expectedRole:          RoleCentralManager,
+		},
+		{
+			name:                  "Unknown role",
+			commands:              version,
+			accessToken:           "explicit-token",
+			expectedAuthenticated: true,
+			expectedRole:          RoleUnknown,
+			expectedVersion:       "12.1 patch 100",
+		},
+		{
+			name:                  "Unit type unsupported and managed units unavailable",
+			commands:              map[string]string{managedUnitsCommand: `{"ErrorCode":3001,"ErrorMessage":"Not a Central Manager"}`},
+			accessToken:           "explicit-token",
+			expectedAuthenticated: true,
+			expectedRole:          RoleUnknown,
+		},
+		{
+			name:         "Without credentials",
+			expectedRole: RoleUnknown,
+		},
+		{
+			name:          "Invalid credentials",
+			tokenStatus:   http.StatusUnauthorized,
+			credentials:   true,
+			expectedRole:  RoleUnknown,
+			expectedError: "could not authenticate",
+		},
+		{
+			name:          "Rejected access token",
+			commands:      version,
+			accessToken:   "rejected-token",
+			expectedRole:  RoleUnknown,
+			expectedError: "could not authenticate",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.tokenStatus == 0 {
+				tc.tokenStatus = http.StatusOK
+			}
+			server := healthServer(t, tc.tokenStatus, tc.commands)
+
+			serverURL := strings.TrimPrefix(server.URL, "https://")
+			urlSplit := strings.Split(serverURL, ":")
+			client := NewClient(urlSplit[0], urlSplit[1])
+			if tc.credentials {
+				client.ConfigureCredentials(Credentials{ClientID: "client1", ClientSecret: "secret", Username: "admin", Password: "password"})
+			}
+			api, err := client.NewSecureClient(writeCertificateAuthority(t, server))
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			health, err := api.Health(context.Background(), tc.accessToken)
+			if tc.expectedError == "" && err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if tc.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedError)) {
+				t.Fatalf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+
+			if !health.Reachable || health.Endpoint != server.URL {
+				t.Errorf("Expected %s to be reachable, got %+v", server.URL, health)
+			}
+			if health.TLS == nil || !health.TLS.Verified || health.TLS.Version == "" {
+				t.Errorf("Expected verified TLS details, got %+v", health.TLS)
+			}
+			if health.Authenticated != tc.expectedAuthenticated || health.User != tc.expectedUser {
+				t.Errorf("Expected authenticated %t as %q, got %t as %q", tc.expectedAuthenticated, tc.expectedUser, health.Authenticated, health.User)
+			}
+			if health.Role != tc.expectedRole {
+				t.Errorf("Expected role %s, got %s", tc.expectedRole, health.Role)
+			}
+			gotVersion := ""
+			if health.Version != nil {
+				gotVersion = health.Version.String()
+			}
+			if gotVersion != tc.expectedVersion {
+				t.Errorf("Expected version %q, got %q", tc.expectedVersion, gotVersion)
+			}
+		})
+	}
+}
+
+func TestHealthUnreachable(t *testing.T) {
+	// Start and immediately stop a server to get an address nothing listens on
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	serverURL := strings.TrimPrefix(server.URL, "https://")
+	urlSplit := strings.Split(serverURL, ":")
+	client := NewClient(urlSplit[0], urlSplit[1])
+	client.ConfigureCredentials(Credentials{ClientID: "client1", ClientSecret: "secret", Username: "admin", Password: "password"})
+
+	// The probe is not retried, with the default retries it would wait at least a second before failing
+	start := time.Now()
+	health, err := client.NewInsecureClient().Health(context.Background(), "")
+	if err == nil || !strings.Contains(err.Error(), "could not reach") {
+		t.Errorf("Expected the appliance to be unreachable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the unreachable appliance to be reported at once, took %s", elapsed)
+	}
+	if health.Reachable || health.Authenticated || health.TLS != nil {
+		t.Errorf("Expected no check to pass, got %+v", health)
+	}
+}
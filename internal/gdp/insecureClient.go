@@ -5,47 +5,29 @@ package gdp
 
 import (
 	"context"
-	"crypto/tls"
 	"net/http"
 )
 
+// InsecureClient calls the Guardium Data Protection API without verifying the appliance certificate
 type InsecureClient struct {
-	Client Client
+	Client *Client
+
+	httpClient *http.Client
 }
 
 func (c *Client) NewInsecureClient() *InsecureClient {
-
 	return &InsecureClient{
-		Client{
-			Host:     c.Host,
-			port:     c.port,
-			protocol: "https",
-		},
+		Client:     c,
+		httpClient: c.insecureHTTPClient(),
 	}
 }
 
 func (i *InsecureClient) ImportProfilesFromFile(ctx context.Context, accessToken, pathToFile string, updateMode bool) error {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.ImportProfilesFromFile(ctx, insecureClient, accessToken, pathToFile, updateMode)
+	return i.Client.ImportProfilesFromFile(ctx, i.httpClient, accessToken, pathToFile, updateMode)
 }
 
-func (i *InsecureClient) GenerateAccessToken(ctx context.Context, clientSecret, username, password, clientId string) (string, error) {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	otr, err := i.Client.generateAccessToken(ctx, insecureClient, clientSecret, username, password, clientId)
+func (i *InsecureClient) GenerateAccessToken(ctx context.Context, credentials Credentials) (string, error) {
+	otr, err := i.Client.generateAccessToken(ctx, i.httpClient, credentials)
 	if err != nil {
 		return "", err
 	}
@@ -54,109 +36,81 @@ func (i *InsecureClient) GenerateAccessToken(ctx context.Context, clientSecret,
 }
 
 func (i *InsecureClient) BulkInstallConnector(ctx context.Context, accessToken, udcName, gdpMuHost string) error {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.BulkInstallConnector(ctx, insecureClient, accessToken, udcName, gdpMuHost)
+	return i.Client.BulkInstallConnector(ctx, i.httpClient, accessToken, udcName, gdpMuHost)
 }
 
 func (i *InsecureClient) CreateAWSSecretsManager(ctx context.Context, accessToken string, config *AWSSecretsManagerConfig) error {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.CreateAWSSecretsManager(ctx, insecureClient, accessToken, config)
+	return i.Client.CreateAWSSecretsManager(ctx, i.httpClient, accessToken, config)
 }
 
 func (i *InsecureClient) GetAWSSecretsManager(ctx context.Context, accessToken string, name string) (*AWSSecretsManagerConfig, error) {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.GetAWSSecretsManager(ctx, insecureClient, accessToken, name)
+	return i.Client.GetAWSSecretsManager(ctx, i.httpClient, accessToken, name)
 }
 
 func (i *InsecureClient) GetExistingAWSSecretsManagerNames(ctx context.Context, accessToken string) ([]string, error) {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.GetExistingAWSSecretsManagerNames(ctx, insecureClient, accessToken)
+	return i.Client.GetExistingAWSSecretsManagerNames(ctx, i.httpClient, accessToken)
 }
 
 func (i *InsecureClient) UpdateAWSSecretsManager(ctx context.Context, accessToken string, config *AWSSecretsManagerConfig) error {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.UpdateAWSSecretsManager(ctx, insecureClient, accessToken, config)
+	return i.Client.UpdateAWSSecretsManager(ctx, i.httpClient, accessToken, config)
 }
 
 func (i *InsecureClient) DeleteAWSSecretsManager(ctx context.Context, accessToken string, name string) error {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.DeleteAWSSecretsManager(ctx, insecureClient, accessToken, name)
+	return i.Client.DeleteAWSSecretsManager(ctx, i.httpClient, accessToken, name)
 }
 
 func (i *InsecureClient) RegisterVADataSource(ctx context.Context, accessToken string, payload []byte) error {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.RegisterVADataSource(ctx, insecureClient, accessToken, payload)
+	return i.Client.RegisterVADataSource(ctx, i.httpClient, accessToken, payload)
 }
 
 func (i *InsecureClient) ConfigureVADataSource(ctx context.Context, accessToken string, payload []byte) error {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	return i.Client.ConfigureVADataSource(ctx, insecureClient, accessToken, payload)
+	return i.Client.ConfigureVADataSource(ctx, i.httpClient, accessToken, payload)
 }
 
 func (i *InsecureClient) ConfigureVANotifications(ctx context.Context, accessToken string, payload []byte) error {
-	insecureClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
+	return i.Client.ConfigureVANotifications(ctx, i.httpClient, accessToken, payload)
+}
+
+// Execute runs an arbitrary GuardAPI command
+func (i *InsecureClient) Execute(ctx context.Context, accessToken string, cmd Command) (*Response, error) {
+	return i.Client.Execute(ctx, i.httpClient, accessToken, cmd)
+}
+
+// ApplianceVersion returns the Guardium version of the appliance, detected once per provider run
+func (i *InsecureClient) ApplianceVersion(ctx context.Context, accessToken string) (ApplianceVersion, error) {
+	return i.Client.ApplianceVersion(ctx, i.httpClient, accessToken)
+}
+
+// RequireCapability fails when the appliance or managed unit commands are sent to is too old for capability
+func (i *InsecureClient) RequireCapability(ctx context.Context, accessToken string, capability Capability) error {
+	return i.Client.RequireCapability(ctx, i.httpClient, accessToken, capability)
+}
 
-	return i.Client.ConfigureVANotifications(ctx, insecureClient, accessToken, payload)
+// ValidateTargetUnit checks that unit is registered with the Central Manager
+func (i *InsecureClient) ValidateTargetUnit(ctx context.Context, accessToken, unit string) error {
+	return i.Client.ValidateTargetUnit(ctx, i.httpClient, accessToken, unit)
+}
+
+// Health checks that the appliance is reachable and accepts the access token, and reports its role and version
+func (i *InsecureClient) Health(ctx context.Context, accessToken string) (*Health, error) {
+	return i.Client.Health(ctx, i.httpClient, accessToken)
+}
+
+// Host returns the appliance host name
+func (i *InsecureClient) Host() string {
+	return i.Client.Host
+}
+
+// HasCredentials reports whether access tokens can be obtained with the provider credentials
+func (i *InsecureClient) HasCredentials() bool {
+	return i.Client.HasCredentials()
+}
+
+// WithCAPath returns a client verifying the appliance certificate against the bundle at caPath. Without a
+// bundle the client keeps skipping verification.
+func (i *InsecureClient) WithCAPath(caPath string) (API, error) {
+	if caPath == "" {
+		return i, nil
+	}
+	return i.Client.NewSecureClient(caPath)
 }
@@ -0,0 +1,44 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"sync"
+)
+
+// contextMutex is a mutex that callers can stop waiting for when their context is done, used where the holder
+// may be busy calling the appliance. The zero value is unlocked.
+type contextMutex struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+func (m *contextMutex) init() {
+	m.once.Do(func() {
+		m.ch = make(chan struct{}, 1)
+	})
+}
+
+// Lock waits for the mutex
+func (m *contextMutex) Lock() {
+	m.init()
+	m.ch <- struct{}{}
+}
+
+// LockContext waits for the mutex until ctx is done, returning the context error in that case
+func (m *contextMutex) LockContext(ctx context.Context) error {
+	m.init()
+	select {
+	case m.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Unlock releases the mutex
+func (m *contextMutex) Unlock() {
+	<-m.ch
+}
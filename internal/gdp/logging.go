@@ -0,0 +1,212 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// redacted replaces secret values in logs
+	redacted = "***"
+
+	// maxLoggedBodySize is the largest request or response body included in debug logs
+	maxLoggedBodySize = 64 * 1024
+)
+
+// secretKeyFragments identify JSON fields, form fields and headers whose values are never logged.
+// Keys are compared case-insensitively with separators removed, so "secret_access_key",
+// "secretAccessKey" and "SecretAccessKey" all match "secret".
+var secretKeyFragments = []string{"password", "secret", "token", "authorization", "apikey", "credential"}
+
+// secretPatterns mask secrets in free text, such as error messages echoing a request
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)bearer\s+[a-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`(?i)"[a-z_]*(password|secret|token)[a-z_]*"\s*:\s*"[^"]*"`),
+}
+
+// isSecretKey reports whether values stored under key must be masked
+func isSecretKey(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(key))
+	for _, fragment := range secretKeyFragments {
+		if strings.Contains(normalized, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithSecretMasking returns a context whose tflog output masks Authorization headers, known secret
+// fields and bearer tokens. Every request made by the gdp client logs with it.
+func WithSecretMasking(ctx context.Context) context.Context {
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "Authorization", "password", "secret_access_key", "client_secret", "access_token", "refresh_token")
+	ctx = tflog.MaskAllFieldValuesRegexes(ctx, secretPatterns...)
+	return tflog.MaskMessageRegexes(ctx, secretPatterns...)
+}
+
+// loggingTransport logs every request sent to the appliance at debug level, with secrets masked
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := WithSecretMasking(req.Context())
+
+	fields := map[string]any{
+		"method":          req.Method,
+		"url":             redactURL(req.URL),
+		"request_headers": redactHeaders(req.Header),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			fields["request_body"] = redactBody(req.Header.Get("Content-Type"), body)
+		}
+	}
+	tflog.Debug(ctx, "sending Guardium Data Protection request", fields)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields = map[string]any{
+		"method":      req.Method,
+		"url":         redactURL(req.URL),
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "Guardium Data Protection request failed", fields)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+	if resp.Body != nil && resp.ContentLength <= maxLoggedBodySize {
+		// Read at most one byte more than is logged, so that the body can be logged and still be read by the
+		// caller. Bodies of unknown length, such as chunked or decompressed ones, may be longer than that.
+		prefix, readErr := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBodySize+1))
+		if readErr == nil && len(prefix) <= maxLoggedBodySize {
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(prefix))
+		} else {
+			// The caller reads the prefix followed by the rest of the body, whatever was read so far
+			resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(prefix), resp.Body), Closer: resp.Body}
+		}
+		if readErr == nil {
+			fields["response_body"] = redactBody(resp.Header.Get("Content-Type"), io.NopCloser(bytes.NewReader(prefix)))
+		}
+	}
+	tflog.Debug(ctx, "received Guardium Data Protection response", fields)
+
+	return resp, nil
+}
+
+func (t *loggingTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
+
+// prefixedBody is a response body whose start was already read, closing the original body
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// redactURL returns u with every query parameter value masked
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	masked := *u
+	query := masked.Query()
+	for key := range query {
+		query[key] = []string{redacted}
+	}
+	masked.RawQuery = query.Encode()
+	return masked.String()
+}
+
+// redactHeaders returns the request headers with secret values masked
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if isSecretKey(name) || strings.EqualFold(name, "Cookie") {
+			headers[name] = redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+
+	return headers
+}
+
+// redactBody returns a JSON or form encoded body with secret fields masked. Other content, such as
+// multipart uploads, is summarized instead of logged.
+func redactBody(contentType string, body io.ReadCloser) string {
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxLoggedBodySize+1))
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+	if len(data) > maxLoggedBodySize {
+		return "<body too large to log>"
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(data))
+		if err != nil {
+			return "<unparseable form body>"
+		}
+		for key := range form {
+			if isSecretKey(key) {
+				form[key] = []string{redacted}
+			}
+		}
+		return form.Encode()
+	case mediaType == "application/json" || mediaType == "" || strings.HasSuffix(mediaType, "+json"):
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return "<non JSON body>"
+		}
+		masked, err := json.Marshal(redactJSON(value))
+		if err != nil {
+			return "<non JSON body>"
+		}
+		return string(masked)
+	default:
+		return "<" + mediaType + " body>"
+	}
+}
+
+// redactJSON masks the values of secret keys anywhere in a decoded JSON document
+func redactJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSecretKey(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactJSON(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,166 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactBody(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    string
+	}{
+		{
+			name:        "JSON secret fields",
+			contentType: "application/json",
+			body:        `{"name":"aws","secret_access_key":"AKIASECRET","datasource":{"dbPassword":"hunter2","host":"db"}}`,
+			expected:    `{"datasource":{"dbPassword":"***","host":"db"},"name":"aws","secret_access_key":"***"}`,
+		},
+		{
+			name:        "JSON array",
+			contentType: "application/json; charset=utf-8",
+			body:        `[{"secretAccessKey":"AKIASECRET","accessKeyId":"AKIA"}]`,
+			expected:    `[{"accessKeyId":"AKIA","secretAccessKey":"***"}]`,
+		},
+		{
+			name:        "Form secret fields",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "client_id=client1&client_secret=s3cret&grant_type=password&password=hunter2&username=user",
+			expected:    "client_id=client1&client_secret=%2A%2A%2A&grant_type=password&password=%2A%2A%2A&username=user",
+		},
+		{
+			name:        "Multipart upload summarized",
+			contentType: "multipart/form-data; boundary=abc",
+			body:        "--abc\r\n",
+			expected:    "<multipart/form-data body>",
+		},
+		{
+			name:        "Empty body",
+			contentType: "application/json",
+			body:        "",
+			expected:    "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := redactBody(tc.contentType, io.NopCloser(strings.NewReader(tc.body)))
+			if got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := url.Parse("https://guardium.example.com:8443/oauth/token?client_secret=s3cret&grant_type=password")
+
+	got := redactURL(u)
+	if strings.Contains(got, "s3cret") || strings.Contains(got, "password") {
+		t.Errorf("Expected query parameters to be masked, got %s", got)
+	}
+	if !strings.HasPrefix(got, "https://guardium.example.com:8443/oauth/token?") {
+		t.Errorf("Expected URL to be kept, got %s", got)
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"aws","secret_access_key":"AKIASECRET"}` {
+			t.Errorf("Expected request body to reach the server unchanged, got %s", string(body))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"issued-token","ID":"1"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logs)
+
+	httpClient := &http.Client{Transport: &loggingTransport{next: http.DefaultTransport}}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/restAPI/aws_secrets_manager?token=query-secret",
+		bytes.NewBufferString(`{"name":"aws","secret_access_key":"AKIASECRET"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer header-token")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The caller still reads the full response body
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"access_token":"issued-token","ID":"1"}` {
+		t.Errorf("Expected response body to be preserved, got %s", string(body))
+	}
+
+	output := logs.String()
+	for _, secret := range []string{"AKIASECRET", "header-token", "issued-token", "query-secret"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Expected %q to be masked in logs:\n%s", secret, output)
+		}
+	}
+	for _, expected := range []string{`"method":"POST"`, `"status":200`, "duration_ms", "/restAPI/aws_secrets_manager"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in logs:\n%s", expected, output)
+		}
+	}
+}
+
+func TestLoggingTransportChunkedBody(t *testing.T) {
+	large := `{"items":"` + strings.Repeat("x", 2*maxLoggedBodySize) + `"}`
+	testCases := map[string]string{
+		"Small": `{"ID":"1"}`,
+		"Large": large,
+	}
+
+	for name, expected := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				// Flushing before the body is written sends it chunked, without a Content-Length
+				w.(http.Flusher).Flush()
+				_, _ = w.Write([]byte(expected))
+			}))
+			defer server.Close()
+
+			var logs bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &logs)
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/restAPI/datasource", nil)
+			resp, err := (&http.Client{Transport: &loggingTransport{next: http.DefaultTransport}}).Do(req)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.ContentLength != -1 {
+				t.Fatalf("Expected a body of unknown length, got %d", resp.ContentLength)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil || string(body) != expected {
+				t.Errorf("Expected the full %d byte body, got %d bytes and %v", len(expected), len(body), err)
+			}
+			if name == "Large" && !strings.Contains(logs.String(), "body too large to log") {
+				t.Errorf("Expected the large body to be summarized in logs:\n%s", logs.String())
+			}
+			if name == "Small" && !strings.Contains(logs.String(), `"response_body":"{\"ID\":\"1\"}"`) {
+				t.Errorf("Expected the small body in logs:\n%s", logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is the number of items requested per page when PageOptions.PageSize is not set
+	DefaultPageSize = 100
+
+	// pageOffsetParam and pageLimitParam select a page of a list command
+	pageOffsetParam = "offset"
+	pageLimitParam  = "limit"
+)
+
+// PageOptions control how the items of a list command are fetched
+type PageOptions struct {
+	// PageSize is the number of items requested at a time, DefaultPageSize when zero
+	PageSize int
+	// Limit is the largest number of items returned, zero for every item
+	Limit int
+}
+
+// Validate reports a negative page size or limit
+func (o PageOptions) Validate() error {
+	if o.PageSize < 0 {
+		return fmt.Errorf("page size must not be negative, got %d", o.PageSize)
+	}
+	if o.Limit < 0 {
+		return fmt.Errorf("limit must not be negative, got %d", o.Limit)
+	}
+	return nil
+}
+
+func (o PageOptions) pageSize() int {
+	if o.PageSize == 0 {
+		return DefaultPageSize
+	}
+	return o.PageSize
+}
+
+// executeFunc runs a command, binding the HTTP client and access token of the caller
+type executeFunc func(ctx context.Context, cmd Command) (*Response, error)
+
+// List returns the items of the GuardAPI list command cmd, which must be a GET command answering with a JSON
+// array, decoded into T. Pages are requested with the offset and limit query parameters as the iteration goes,
+// until a short page is returned or opts.Limit items were returned. Appliances ignoring the page parameters
+// answer with the whole collection, which is returned once, and appliances rejecting them with a 400 status
+// are asked for the whole collection without them, as are appliances honoring the limit but ignoring the
+// offset. The iteration stops at the first error.
+func List[T any](ctx context.Context, e Executor, accessToken string, cmd Command, opts PageOptions) iter.Seq2[T, error] {
+	return list[T](ctx, func(ctx context.Context, cmd Command) (*Response, error) {
+		return e.Execute(ctx, accessToken, cmd)
+	}, cmd, opts)
+}
+
+func list[T any](ctx context.Context, execute executeFunc, cmd Command, opts PageOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if err := opts.Validate(); err != nil {
+			yield(zero, err)
+			return
+		}
+		if cmd.Method != "" && cmd.Method != http.MethodGet {
+			yield(zero, fmt.Errorf("list command %s must use GET, got %s", cmd.Name, cmd.Method))
+			return
+		}
+
+		params := url.Values{}
+		if cmd.Params != nil {
+			var err error
+			if params, err = queryValues(cmd.Params); err != nil {
+				yield(zero, fmt.Errorf("error encoding %s parameters: %w", cmd.Name, err))
+				return
+			}
+		}
+
+		returned := 0
+		var previousFirst json.RawMessage
+		for offset := 0; ; {
+			size := opts.pageSize()
+			if opts.Limit > 0 {
+				size = min(size, opts.Limit-returned)
+			}
+
+			pageParams := make(url.Values, len(params)+2)
+			for key, values := range params {
+				pageParams[key] = values
+			}
+			pageParams.Set(pageOffsetParam, strconv.Itoa(offset))
+			pageParams.Set(pageLimitParam, strconv.Itoa(size))
+			pageCmd := cmd
+			pageCmd.Method = http.MethodGet
+			pageCmd.Params = pageParams
+
+			page, err := fetchPage(ctx, execute, pageCmd)
+			// An appliance rejecting the page parameters is asked for the whole collection instead
+			unpaged := offset == 0 && hasStatus(err, http.StatusBadRequest)
+			// An appliance ignoring the offset answers every page with the same first item. It is asked for the
+			// whole collection instead, skipping the items already returned.
+			if err == nil && len(page) > 0 && previousFirst != nil && bytes.Equal(page[0], previousFirst) {
+				unpaged = true
+			}
+			if unpaged {
+				pageCmd.Params = params
+				if page, err = fetchPage(ctx, execute, pageCmd); err == nil {
+					page = page[min(offset, len(page)):]
+				}
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			// An appliance ignoring the limit answers with the whole collection
+			unpaged = unpaged || len(page) > size
+
+			for _, raw := range page {
+				var item T
+				if err := json.Unmarshal(raw, &item); err != nil {
+					yield(zero, fmt.Errorf("error parsing %s item: %w", cmd.Name, err))
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+				returned++
+				if opts.Limit > 0 && returned >= opts.Limit {
+					return
+				}
+			}
+
+			if unpaged || len(page) < size {
+				return
+			}
+			if len(page) > 0 {
+				previousFirst = page[0]
+			}
+			offset += len(page)
+		}
+	}
+}
+
+// fetchPage runs cmd and decodes the JSON array it answers with
+func fetchPage(ctx context.Context, execute executeFunc, cmd Command) ([]json.RawMessage, error) {
+	resp, err := execute(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	page, err := decode[[]json.RawMessage](resp)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s page: %w", cmd.Name, err)
+	}
+	return page, nil
+}
+
+// ListAll returns every item of the GuardAPI list command cmd, see List
+func ListAll[T any](ctx context.Context, e Executor, accessToken string, cmd Command, opts PageOptions) ([]T, error) {
+	return collect(List[T](ctx, e, accessToken, cmd, opts))
+}
+
+// collect gathers the items of a list iteration, stopping at the first error
+func collect[T any](items iter.Seq2[T, error]) ([]T, error) {
+	var all []T
+	for item, err := range items {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, item)
+	}
+	return all, nil
+}
@@ -0,0 +1,238 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// pagedServer serves items as the list command, one page per request unless ignorePaging is set, and records
+// the query of every request. With ignoreOffset set every page starts at the first item, with rejectPaging set
+// requests with page parameters are answered with a 400 status.
+type pagedServer struct {
+	items        []int
+	ignorePaging bool
+	ignoreOffset bool
+	rejectPaging bool
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func (s *pagedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.queries = append(s.queries, r.URL.RawQuery)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if s.rejectPaging && r.URL.Query().Has("offset") {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"ErrorCode":400,"ErrorMessage":"Unknown parameter offset"}`))
+		return
+	}
+
+	page := s.items
+	if !s.ignorePaging && r.URL.Query().Has("limit") {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if s.ignoreOffset {
+			offset = 0
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page = s.items[min(offset, len(s.items)):min(offset+limit, len(s.items))]
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// newListClient returns a client for a TLS server serving handler
+func newListClient(t *testing.T, handler http.Handler) API {
+	t.Helper()
+
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	serverURL := strings.TrimPrefix(server.URL, "https://")
+	urlSplit := strings.Split(serverURL, ":")
+	return NewClient(urlSplit[0], urlSplit[1]).NewInsecureClient()
+}
+
+func TestList(t *testing.T) {
+	testCases := []struct {
+		name            string
+		server          *pagedServer
+		cmd             Command
+		opts            PageOptions
+		expected        []int
+		expectedQueries []string
+	}{
+		{
+			name:            "Every page",
+			server:          &pagedServer{items: []int{1, 2, 3, 4, 5}},
+			opts:            PageOptions{PageSize: 2},
+			expected:        []int{1, 2, 3, 4, 5},
+			expectedQueries: []string{"limit=2&offset=0", "limit=2&offset=2", "limit=2&offset=4"},
+		},
+		{
+			name:            "Last page full",
+			server:          &pagedServer{items: []int{1, 2, 3, 4}},
+			opts:            PageOptions{PageSize: 2},
+			expected:        []int{1, 2, 3, 4},
+			expectedQueries: []string{"limit=2&offset=0", "limit=2&offset=2", "limit=2&offset=4"},
+		},
+		{
+			name:            "Default page size",
+			server:          &pagedServer{items: []int{1, 2, 3}},
+			expected:        []int{1, 2, 3},
+			expectedQueries: []string{"limit=100&offset=0"},
+		},
+		{
+			name:            "Limit",
+			server:          &pagedServer{items: []int{1, 2, 3, 4, 5}},
+			opts:            PageOptions{PageSize: 2, Limit: 3},
+			expected:        []int{1, 2, 3},
+			expectedQueries: []string{"limit=2&offset=0", "limit=1&offset=2"},
+		},
+		{
+			name:            "Parameters",
+			server:          &pagedServer{items: []int{1}},
+			cmd:             Command{Params: map[string]string{"type": "DB2"}},
+			expected:        []int{1},
+			expectedQueries: []string{"limit=100&offset=0&type=DB2"},
+		},
+		{
+			name:            "Paging ignored",
+			server:          &pagedServer{items: []int{1, 2, 3, 4, 5}, ignorePaging: true},
+			opts:            PageOptions{PageSize: 2},
+			expected:        []int{1, 2, 3, 4, 5},
+			expectedQueries: []string{"limit=2&offset=0"},
+		},
+		{
+			name:            "Paging ignored with a full page",
+			server:          &pagedServer{items: []int{1, 2}, ignorePaging: true},
+			opts:            PageOptions{PageSize: 2},
+			expected:        []int{1, 2},
+			expectedQueries: []string{"limit=2&offset=0", "limit=2&offset=2", ""},
+		},
+		{
+			name:            "Offset ignored",
+			server:          &pagedServer{items: []int{1, 2, 3, 4, 5}, ignoreOffset: true},
+			opts:            PageOptions{PageSize: 2},
+			expected:        []int{1, 2, 3, 4, 5},
+			expectedQueries: []string{"limit=2&offset=0", "limit=2&offset=2", ""},
+		},
+		{
+			name:            "Offset ignored with a limit",
+			server:          &pagedServer{items: []int{1, 2, 3, 4, 5}, ignoreOffset: true},
+			opts:            PageOptions{PageSize: 2, Limit: 3},
+			expected:        []int{1, 2, 3},
+			expectedQueries: []string{"limit=2&offset=0", "limit=1&offset=2", ""},
+		},
+		{
+			name:            "Paging rejected",
+			server:          &pagedServer{items: []int{1, 2, 3, 4, 5}, rejectPaging: true},
+			opts:            PageOptions{PageSize: 2, Limit: 4},
+			expected:        []int{1, 2, 3, 4},
+			expectedQueries: []string{"limit=2&offset=0", ""},
+		},
+		{
+			name:            "Paging rejected with parameters",
+			server:          &pagedServer{items: []int{1}, rejectPaging: true},
+			cmd:             Command{Params: map[string]string{"type": "DB2"}},
+			expected:        []int{1},
+			expectedQueries: []string{"limit=100&offset=0&type=DB2", "type=DB2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cmd.Name = "datasource"
+			items, err := ListAll[int](context.Background(), newListClient(t, tc.server), "test-token", tc.cmd, tc.opts)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if !slices.Equal(items, tc.expected) {
+				t.Errorf("Expected items %v, got %v", tc.expected, items)
+			}
+			if !slices.Equal(tc.server.queries, tc.expectedQueries) {
+				t.Errorf("Expected queries %v, got %v", tc.expectedQueries, tc.server.queries)
+			}
+		})
+	}
+}
+
+func TestListStopsEarly(t *testing.T) {
+	paged := &pagedServer{items: []int{1, 2, 3, 4, 5}}
+	for item, err := range List[int](context.Background(), newListClient(t, paged), "test-token", Command{Name: "datasource"}, PageOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if item == 2 {
+			break
+		}
+	}
+	if len(paged.queries) != 1 {
+		t.Errorf("Expected only the first page to be requested, got %v", paged.queries)
+	}
+}
+
+func TestGetAllAWSSecretsManagerConfigs(t *testing.T) {
+	// More configurations than a page holds are returned by a single request
+	items := make([]string, 150)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id":%d,"name":"aws-%d","authType":"access_key"}`, i, i)
+	}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+	defer server.Close()
+
+	configs, err := newGuardAPITestClient(server).GetAllAWSSecretsManagerConfigs(context.Background(), server.Client(), "test-token")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(configs) != len(items) || configs[149].Name != "aws-149" {
+		t.Errorf("Expected %d configurations, got %d", len(items), len(configs))
+	}
+	if !slices.Equal(queries, []string{""}) {
+		t.Errorf("Expected a single request without page parameters, got %v", queries)
+	}
+}
+
+func TestListErrors(t *testing.T) {
+	ctx := context.Background()
+	api := newListClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("offset") != "0" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"ErrorCode":500,"ErrorMessage":"Internal error"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"name":"db2-prod"},{"name":"db2-test"}]`))
+	}))
+	list := Command{Name: "datasource"}
+
+	if _, err := ListAll[map[string]string](ctx, api, "test-token", list, PageOptions{PageSize: 2}); err == nil || !strings.Contains(err.Error(), "Internal error") {
+		t.Errorf("Expected the failed second page to be reported, got %v", err)
+	}
+	if _, err := ListAll[string](ctx, api, "test-token", list, PageOptions{PageSize: 1}); err == nil || !strings.Contains(err.Error(), "error parsing datasource item") {
+		t.Errorf("Expected an item that does not decode to be reported, got %v", err)
+	}
+	if _, err := ListAll[map[string]string](ctx, api, "test-token", Command{Method: http.MethodPost, Name: "datasource"}, PageOptions{}); err == nil || !strings.Contains(err.Error(), "must use GET") {
+		t.Errorf("Expected a POST list to be rejected, got %v", err)
+	}
+	if _, err := ListAll[map[string]string](ctx, api, "test-token", list, PageOptions{Limit: -1}); err == nil || !strings.Contains(err.Error(), "limit must not be negative") {
+		t.Errorf("Expected a negative limit to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyConfig describes the outbound proxy used to reach the appliance. Empty fields fall back to the
+// standard HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+type ProxyConfig struct {
+	// URL of the proxy, e.g. "http://proxy.example.com:3128". Supported schemes are http, https and socks5
+	URL string
+	// NoProxy is a comma separated list of hosts, domains and CIDR ranges reached without the proxy,
+	// using the NO_PROXY syntax
+	NoProxy string
+}
+
+// proxyFunc returns the function selecting the proxy for each request
+func (p ProxyConfig) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	config := httpproxy.FromEnvironment()
+
+	if p.URL != "" {
+		proxyURL, err := url.Parse(p.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", p.URL)
+		}
+		if proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: missing host", p.URL)
+		}
+
+		config.HTTPProxy = p.URL
+		config.HTTPSProxy = p.URL
+	}
+	if p.NoProxy != "" {
+		config.NoProxy = p.NoProxy
+	}
+
+	proxy := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// ConfigureProxy sets the outbound proxy used by every client created from c
+func (c *Client) ConfigureProxy(config ProxyConfig) error {
+	proxy, err := config.proxyFunc()
+	if err != nil {
+		return err
+	}
+
+	c.proxy = proxy
+	c.resetHTTPClients()
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProxyConfig(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        ProxyConfig
+		requestURL    string
+		expectedProxy string
+		expectError   bool
+	}{
+		{
+			name:          "Proxy URL",
+			config:        ProxyConfig{URL: "http://proxy.example.com:3128"},
+			requestURL:    "https://guardium.example.com:8443/restAPI/bulkInstall",
+			expectedProxy: "http://proxy.example.com:3128",
+		},
+		{
+			name:       "Host excluded by no_proxy",
+			config:     ProxyConfig{URL: "http://proxy.example.com:3128", NoProxy: "localhost,.example.com"},
+			requestURL: "https://guardium.example.com:8443/restAPI/bulkInstall",
+		},
+		{
+			name:          "Host not excluded by no_proxy",
+			config:        ProxyConfig{URL: "socks5://proxy.example.com:1080", NoProxy: ".internal"},
+			requestURL:    "https://guardium.example.com:8443/restAPI/bulkInstall",
+			expectedProxy: "socks5://proxy.example.com:1080",
+		},
+		{
+			name:        "Unsupported scheme",
+			config:      ProxyConfig{URL: "ftp://proxy.example.com"},
+			expectError: true,
+		},
+		{
+			name:        "Missing host",
+			config:      ProxyConfig{URL: "proxy.example.com:3128"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("HTTPS_PROXY", "")
+			t.Setenv("NO_PROXY", "")
+
+			proxy, err := tc.config.proxyFunc()
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, tc.requestURL, nil)
+			proxyURL, err := proxy(req)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			got := ""
+			if proxyURL != nil {
+				got = proxyURL.String()
+			}
+			if got != tc.expectedProxy {
+				t.Errorf("Expected proxy %q, got %q", tc.expectedProxy, got)
+			}
+		})
+	}
+}
+
+func TestConfigureProxy(t *testing.T) {
+	var proxied atomic.Int32
+
+	// A plain HTTP proxy receives the absolute request URL
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		if r.URL.Host != "guardium.example.com:8443" {
+			t.Errorf("Expected request for guardium.example.com:8443, got %s", r.URL.Host)
+		}
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	defer proxy.Close()
+
+	client := NewClient("guardium.example.com", "8443")
+	client.protocol = "http"
+	if err := client.ConfigureProxy(ProxyConfig{URL: proxy.URL}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if err := client.NewInsecureClient().BulkInstallConnector(context.Background(), "token", "connector-profile", "host1.example.com"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if got := proxied.Load(); got != 1 {
+		t.Errorf("Expected 1 proxied request, got %d", got)
+	}
+}
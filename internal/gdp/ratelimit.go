@@ -0,0 +1,202 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitConfig bounds the load put on a single appliance
+type RateLimitConfig struct {
+	// MaxConcurrent is the number of requests in flight at once, 0 removes the limit
+	MaxConcurrent int
+	// RequestsPerSecond is the sustained request rate, 0 removes the limit
+	RequestsPerSecond float64
+	// Burst is the number of requests that may exceed RequestsPerSecond at once, defaults to 1
+	Burst int
+}
+
+// DefaultRateLimitConfig returns the limits used when the provider does not configure any. Terraform runs
+// 10 operations in parallel by default, which is more than a collector handles reliably.
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		MaxConcurrent: 4,
+	}
+}
+
+// Validate reports negative limits
+func (r RateLimitConfig) Validate() error {
+	if r.MaxConcurrent < 0 || r.RequestsPerSecond < 0 || r.Burst < 0 {
+		return fmt.Errorf("rate limits must not be negative")
+	}
+	return nil
+}
+
+// strictest returns the tighter of the limits of r and other, for clients of the same appliance configured
+// differently
+func (r RateLimitConfig) strictest(other RateLimitConfig) RateLimitConfig {
+	return RateLimitConfig{
+		MaxConcurrent:     minLimit(r.MaxConcurrent, other.MaxConcurrent),
+		RequestsPerSecond: minLimit(r.RequestsPerSecond, other.RequestsPerSecond),
+		Burst:             minLimit(r.Burst, other.Burst),
+	}
+}
+
+// minLimit returns the smaller of a and b, 0 standing for no limit
+func minLimit[T int | float64](a, b T) T {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
+
+// hostLimiter holds the concurrency and rate budget of one appliance
+type hostLimiter struct {
+	mu      sync.Mutex
+	slots   chan struct{}
+	limiter *rate.Limiter
+	// config is the configured limits, nil while the defaults apply
+	config *RateLimitConfig
+}
+
+var (
+	hostLimitersMu sync.Mutex
+	// hostLimiters is shared by every Client so that provider aliases and data sources targeting the same
+	// appliance draw from one budget
+	hostLimiters = map[string]*hostLimiter{}
+)
+
+// limiterFor returns the limiter of the appliance at host, creating it on first use
+func limiterFor(host string) *hostLimiter {
+	hostLimitersMu.Lock()
+	defer hostLimitersMu.Unlock()
+
+	l, ok := hostLimiters[host]
+	if !ok {
+		l = &hostLimiter{}
+		l.setLimits(DefaultRateLimitConfig())
+		hostLimiters[host] = l
+	}
+	return l
+}
+
+// configure replaces the default limits. Limits configured by another client of the appliance are only
+// tightened, so that every client stays within the strictest. Requests already holding a slot release it to
+// the previous semaphore.
+func (l *hostLimiter) configure(config RateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.config != nil {
+		config = l.config.strictest(config)
+	}
+	l.config = &config
+	l.setLimits(config)
+}
+
+// setLimits replaces the semaphore and rate limiter. Callers must hold mu.
+func (l *hostLimiter) setLimits(config RateLimitConfig) {
+	l.slots = nil
+	if config.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+
+	l.limiter = nil
+	if config.RequestsPerSecond > 0 {
+		l.limiter = rate.NewLimiter(rate.Limit(config.RequestsPerSecond), max(config.Burst, 1))
+	}
+}
+
+// acquire waits for a request slot and a rate token, returning the function releasing the slot
+func (l *hostLimiter) acquire(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	slots, limiter := l.slots, l.limiter
+	l.mu.Unlock()
+
+	release := func() {}
+	if slots != nil {
+		select {
+		case slots <- struct{}{}:
+			release = func() { <-slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// ConfigureRateLimit sets the concurrency and rate limits of the appliance c connects to. The limits are
+// shared with every other Client for the same host and port, the strictest applying when they differ.
+func (c *Client) ConfigureRateLimit(config RateLimitConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	limiterFor(c.hostKey()).configure(config)
+	return nil
+}
+
+// hostKey identifies the appliance c connects to
+func (c *Client) hostKey() string {
+	return c.Host + ":" + c.port
+}
+
+// limitTransport holds a slot of the appliance budget for every request until its response body is closed.
+// Transports above it close a response before sending another request, so that a request never waits for
+// a slot it holds itself.
+type limitTransport struct {
+	next    http.RoundTripper
+	limiter *hostLimiter
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+func (t *limitTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
+
+// releaseOnClose releases a request slot once the response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
@@ -0,0 +1,205 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	defer server.Close()
+
+	// Two clients for the same appliance, as with provider aliases, share one budget. The second removing the
+	// limit does not loosen the limit of the first.
+	first := newTestClient(server)
+	second := newTestClient(server)
+	if err := first.ConfigureRateLimit(RateLimitConfig{MaxConcurrent: 2}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := second.ConfigureRateLimit(RateLimitConfig{}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		client := first
+		if i%2 == 1 {
+			client = second
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.NewInsecureClient().BulkInstallConnector(context.Background(), "test-token", "connector-profile", "host1.example.com"); err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got != 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", got)
+	}
+}
+
+func TestRateLimitRequestsPerSecond(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	if err := client.ConfigureRateLimit(RateLimitConfig{RequestsPerSecond: 20, Burst: 1}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := client.NewInsecureClient().BulkInstallConnector(context.Background(), "test-token", "connector-profile", "host1.example.com"); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	// The first request uses the burst, the remaining four wait 50ms each
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("Expected requests to be spread over at least 200ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTokenRefresh(t *testing.T) {
+	// The rejection is too large to be logged, so it is only read by authTransport
+	rejection := strings.Repeat("x", 2*maxLoggedBodySize)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == oauthTokenPath {
+			_, _ = w.Write([]byte(`{"access_token":"token-2"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(rejection)))
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(rejection))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.ConfigureCredentials(Credentials{ClientID: "client1", ClientSecret: "secret", Username: "user", Password: "pass"})
+	client.token, client.tokenRefreshAt = "token-1", time.Now().Add(time.Hour)
+	if err := client.ConfigureRateLimit(RateLimitConfig{MaxConcurrent: 1}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// A single slot is enough, the rejection is closed before the token is refreshed
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.NewInsecureClient().BulkInstallConnector(ctx, "", "connector-profile", "host1.example.com"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+}
+
+func TestRateLimitHoldsSlotUntilBodyClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ID":"1"}`))
+	}))
+	defer server.Close()
+
+	l := &hostLimiter{}
+	l.configure(RateLimitConfig{MaxConcurrent: 1})
+	httpClient := &http.Client{Transport: &limitTransport{next: http.DefaultTransport, limiter: l}}
+
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// The slot is held while the body of the first response is open
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := httpClient.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected to wait for the slot, got %v", err)
+	}
+
+	resp.Body.Close()
+	resp, err = httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected the slot to be released once the body was closed, got: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestRateLimitConfigStrictest(t *testing.T) {
+	testCases := []struct {
+		first, second, expected RateLimitConfig
+	}{
+		{first: RateLimitConfig{MaxConcurrent: 2}, second: RateLimitConfig{}, expected: RateLimitConfig{MaxConcurrent: 2}},
+		{first: RateLimitConfig{MaxConcurrent: 8}, second: RateLimitConfig{MaxConcurrent: 4}, expected: RateLimitConfig{MaxConcurrent: 4}},
+		{
+			first:    RateLimitConfig{MaxConcurrent: 4, RequestsPerSecond: 5, Burst: 3},
+			second:   RateLimitConfig{RequestsPerSecond: 10, Burst: 1},
+			expected: RateLimitConfig{MaxConcurrent: 4, RequestsPerSecond: 5, Burst: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.first.strictest(tc.second); got != tc.expected {
+			t.Errorf("Expected %+v for %+v and %+v, got %+v", tc.expected, tc.first, tc.second, got)
+		}
+	}
+}
+
+func TestRateLimitContextCancelled(t *testing.T) {
+	l := &hostLimiter{}
+	l.configure(RateLimitConfig{MaxConcurrent: 1})
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded waiting for a slot, got %v", err)
+	}
+}
+
+func TestRateLimitConfigValidate(t *testing.T) {
+	if err := (RateLimitConfig{MaxConcurrent: -1}).Validate(); err == nil {
+		t.Error("Expected error for negative limit but got nil")
+	}
+	if err := DefaultRateLimitConfig().Validate(); err != nil {
+		t.Errorf("Expected default limits to be valid, got: %v", err)
+	}
+	if got := DefaultRateLimitConfig(); got.MaxConcurrent != 4 {
+		t.Errorf("Expected 4 concurrent requests by default, got %+v", got)
+	}
+}
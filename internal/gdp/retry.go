@@ -0,0 +1,196 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RetryConfig controls how requests failing with transient errors are retried
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts per request, 1 disables retries
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryConfig returns the retry settings used when the provider does not configure any
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 4,
+		MinBackoff:  1 * time.Second,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent marks requests made with ctx as safe to repeat, allowing them to be retried
+// after failures where the appliance may already have processed them
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+type withoutRetryKey struct{}
+
+// withoutRetry marks requests made with ctx to be sent once, so that their failures are reported at once
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutRetryKey{}, true)
+}
+
+// isIdempotent reports whether req can be sent again without changing the outcome
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	idempotent, _ := req.Context().Value(idempotentKey{}).(bool)
+	return idempotent
+}
+
+// retryTransport retries requests failing with transient errors using exponential backoff with jitter.
+// Requests the appliance did not process (connection refused, 429, 503) are retried regardless of method,
+// anything else (502, 504, connection reset) only when the request is idempotent.
+type retryTransport struct {
+	next   http.RoundTripper
+	config RetryConfig
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if once, _ := ctx.Value(withoutRetryKey{}).(bool); once {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+
+		if attempt >= t.config.MaxAttempts || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		// Only retry when the body can be sent again
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		wait := t.backoff(attempt, resp)
+		fields := map[string]any{
+			"method":  req.Method,
+			"url":     req.URL.Redacted(),
+			"attempt": attempt,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["statusCode"] = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Warn(ctx, "retrying Guardium Data Protection request after transient failure", fields)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *retryTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
+
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+
+		// Certificate and name resolution failures will not resolve themselves between attempts
+		var dnsErr *net.DNSError
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) || (errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+			return false
+		}
+
+		// The connection was never established so the appliance cannot have seen the request
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+
+		var netErr net.Error
+		transient := errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+			(errors.As(err, &netErr) && netErr.Timeout())
+		return transient && isIdempotent(req)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	}
+
+	return false
+}
+
+// backoff returns the wait before the next attempt, honouring Retry-After when the appliance sends it
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(retryAfter, t.config.MaxBackoff)
+		}
+	}
+
+	wait := t.config.MinBackoff << (attempt - 1)
+	if wait <= 0 || wait > t.config.MaxBackoff {
+		wait = t.config.MaxBackoff
+	}
+
+	// Equal jitter keeps at least half the backoff while spreading out concurrent retries
+	half := wait / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,194 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	testCases := []struct {
+		name             string
+		method           string
+		idempotent       bool
+		statuses         []int
+		expectedAttempts int32
+		expectedStatus   int
+	}{
+		{
+			name:             "GET retried until success",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectedAttempts: 3,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "POST retried on 503",
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "POST retried on 429",
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "Non-idempotent POST not retried on 502",
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusBadGateway, http.StatusOK},
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusBadGateway,
+		},
+		{
+			name:             "Idempotent POST retried on 502",
+			method:           http.MethodPost,
+			idempotent:       true,
+			statuses:         []int{http.StatusBadGateway, http.StatusOK},
+			expectedAttempts: 2,
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "Client errors not retried",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusBadRequest, http.StatusOK},
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "Attempts exhausted",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 3,
+			expectedStatus:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := attempts.Add(1)
+
+				// The body must be replayed on every attempt
+				body, _ := io.ReadAll(r.Body)
+				if r.Method == http.MethodPost && string(body) != `{"name":"test"}` {
+					t.Errorf("Expected body to be replayed, got %q", string(body))
+				}
+
+				w.WriteHeader(tc.statuses[attempt-1])
+			}))
+			defer server.Close()
+
+			httpClient := &http.Client{
+				Transport: &retryTransport{
+					next:   http.DefaultTransport,
+					config: RetryConfig{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+				},
+			}
+
+			ctx := context.Background()
+			if tc.idempotent {
+				ctx = WithIdempotent(ctx)
+			}
+
+			var body io.Reader
+			if tc.method == http.MethodPost {
+				body = bytes.NewBufferString(`{"name":"test"}`)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, tc.method, server.URL, body)
+			if err != nil {
+				t.Fatalf("Error creating request: %v", err)
+			}
+
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+			if got := attempts.Load(); got != tc.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tc.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestRetryTransportConnectionRefused(t *testing.T) {
+	// Start and immediately stop a server to get an address nothing listens on
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serverURL := server.URL
+	server.Close()
+
+	var attempts atomic.Int32
+	httpClient := &http.Client{
+		Transport: &retryTransport{
+			next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempts.Add(1)
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+			config: RetryConfig{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+		},
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, serverURL, bytes.NewBufferString("{}"))
+	if err != nil {
+		t.Fatalf("Error creating request: %v", err)
+	}
+
+	if _, err := httpClient.Do(req); err == nil {
+		t.Error("Expected error but got nil")
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("Expected refused connections to be retried 3 times, got %d", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "Seconds", value: "5", expected: 5 * time.Second, ok: true},
+		{name: "Past date", value: "Mon, 02 Jan 2006 15:04:05 GMT", expected: 0, ok: true},
+		{name: "Empty", value: "", ok: false},
+		{name: "Invalid", value: "soon", ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tc.value)
+			if ok != tc.ok {
+				t.Fatalf("Expected ok %v, got %v", tc.ok, ok)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
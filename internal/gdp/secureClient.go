@@ -0,0 +1,126 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"net/http"
+)
+
+// SecureClient calls the Guardium Data Protection API over TLS, verifying the appliance certificate
+// against the certificate authority bundle at CACertPath, or the provider level TLS settings when it is empty
+type SecureClient struct {
+	Client     *Client
+	CACertPath string
+
+	httpClient *http.Client
+}
+
+// NewSecureClient returns a client using the provider level TLS settings. When caCertPath is set the
+// PEM encoded certificate authority bundle it points to replaces the trusted roots.
+func (c *Client) NewSecureClient(caCertPath string) (*SecureClient, error) {
+	httpClient, err := c.secureHTTPClient(caCertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecureClient{
+		Client:     c,
+		CACertPath: caCertPath,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (s *SecureClient) ImportProfilesFromFile(ctx context.Context, accessToken, pathToFile string, updateMode bool) error {
+	return s.Client.ImportProfilesFromFile(ctx, s.httpClient, accessToken, pathToFile, updateMode)
+}
+
+func (s *SecureClient) GenerateAccessToken(ctx context.Context, credentials Credentials) (string, error) {
+	otr, err := s.Client.generateAccessToken(ctx, s.httpClient, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	return otr.AccessToken, nil
+}
+
+func (s *SecureClient) BulkInstallConnector(ctx context.Context, accessToken, udcName, gdpMuHost string) error {
+	return s.Client.BulkInstallConnector(ctx, s.httpClient, accessToken, udcName, gdpMuHost)
+}
+
+func (s *SecureClient) CreateAWSSecretsManager(ctx context.Context, accessToken string, config *AWSSecretsManagerConfig) error {
+	return s.Client.CreateAWSSecretsManager(ctx, s.httpClient, accessToken, config)
+}
+
+func (s *SecureClient) GetAWSSecretsManager(ctx context.Context, accessToken string, name string) (*AWSSecretsManagerConfig, error) {
+	return s.Client.GetAWSSecretsManager(ctx, s.httpClient, accessToken, name)
+}
+
+func (s *SecureClient) GetExistingAWSSecretsManagerNames(ctx context.Context, accessToken string) ([]string, error) {
+	return s.Client.GetExistingAWSSecretsManagerNames(ctx, s.httpClient, accessToken)
+}
+
+func (s *SecureClient) UpdateAWSSecretsManager(ctx context.Context, accessToken string, config *AWSSecretsManagerConfig) error {
+	return s.Client.UpdateAWSSecretsManager(ctx, s.httpClient, accessToken, config)
+}
+
+func (s *SecureClient) DeleteAWSSecretsManager(ctx context.Context, accessToken string, name string) error {
+	return s.Client.DeleteAWSSecretsManager(ctx, s.httpClient, accessToken, name)
+}
+
+func (s *SecureClient) RegisterVADataSource(ctx context.Context, accessToken string, payload []byte) error {
+	return s.Client.RegisterVADataSource(ctx, s.httpClient, accessToken, payload)
+}
+
+func (s *SecureClient) ConfigureVADataSource(ctx context.Context, accessToken string, payload []byte) error {
+	return s.Client.ConfigureVADataSource(ctx, s.httpClient, accessToken, payload)
+}
+
+func (s *SecureClient) ConfigureVANotifications(ctx context.Context, accessToken string, payload []byte) error {
+	return s.Client.ConfigureVANotifications(ctx, s.httpClient, accessToken, payload)
+}
+
+// Execute runs an arbitrary GuardAPI command
+func (s *SecureClient) Execute(ctx context.Context, accessToken string, cmd Command) (*Response, error) {
+	return s.Client.Execute(ctx, s.httpClient, accessToken, cmd)
+}
+
+// ApplianceVersion returns the Guardium version of the appliance, detected once per provider run
+func (s *SecureClient) ApplianceVersion(ctx context.Context, accessToken string) (ApplianceVersion, error) {
+	return s.Client.ApplianceVersion(ctx, s.httpClient, accessToken)
+}
+
+// RequireCapability fails when the appliance or managed unit commands are sent to is too old for capability
+func (s *SecureClient) RequireCapability(ctx context.Context, accessToken string, capability Capability) error {
+	return s.Client.RequireCapability(ctx, s.httpClient, accessToken, capability)
+}
+
+// ValidateTargetUnit checks that unit is registered with the Central Manager
+func (s *SecureClient) ValidateTargetUnit(ctx context.Context, accessToken, unit string) error {
+	return s.Client.ValidateTargetUnit(ctx, s.httpClient, accessToken, unit)
+}
+
+// Health checks that the appliance is reachable and accepts the access token, and reports its role and version
+func (s *SecureClient) Health(ctx context.Context, accessToken string) (*Health, error) {
+	return s.Client.Health(ctx, s.httpClient, accessToken)
+}
+
+// Host returns the appliance host name
+func (s *SecureClient) Host() string {
+	return s.Client.Host
+}
+
+// HasCredentials reports whether access tokens can be obtained with the provider credentials
+func (s *SecureClient) HasCredentials() bool {
+	return s.Client.HasCredentials()
+}
+
+// WithCAPath returns a client verifying the appliance certificate against the bundle at caPath, sharing the
+// connections of every other client using the same bundle
+func (s *SecureClient) WithCAPath(caPath string) (API, error) {
+	if caPath == s.CACertPath {
+		return s, nil
+	}
+	return s.Client.NewSecureClient(caPath)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeCertificateAuthority writes the certificate of the given TLS test server to a PEM file
+func writeCertificateAuthority(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0o600); err != nil {
+		t.Fatalf("Error writing certificate authority: %v", err)
+	}
+
+	return caPath
+}
+
+// writeUnrelatedCertificateAuthority writes a freshly generated self-signed certificate to a PEM file
+func writeUnrelatedCertificateAuthority(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Unrelated CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %v", err)
+	}
+
+	caPath := filepath.Join(t.TempDir(), "unrelated.pem")
+	if err := os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Error writing certificate authority: %v", err)
+	}
+
+	return caPath
+}
+
+func TestSecureClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	defer server.Close()
+
+	emptyCAPath := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(emptyCAPath, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Error writing certificate authority: %v", err)
+	}
+
+	serverURL := strings.TrimPrefix(server.URL, "https://")
+	urlSplit := strings.Split(serverURL, ":")
+
+	testCases := []struct {
+		name            string
+		caPath          string
+		expectLoadError bool
+		expectCallError bool
+	}{
+		{
+			name:   "Trusted certificate authority",
+			caPath: writeCertificateAuthority(t, server),
+		},
+		{
+			name:            "Untrusted certificate authority",
+			caPath:          writeUnrelatedCertificateAuthority(t),
+			expectCallError: true,
+		},
+		{
+			name:            "Missing certificate authority",
+			caPath:          filepath.Join(t.TempDir(), "missing.pem"),
+			expectLoadError: true,
+		},
+		{
+			name:            "Invalid certificate authority",
+			caPath:          emptyCAPath,
+			expectLoadError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(urlSplit[0], urlSplit[1])
+
+			secureClient, err := client.NewSecureClient(tc.caPath)
+			if tc.expectLoadError {
+				if err == nil {
+					t.Error("Expected error loading certificate authority but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error loading certificate authority but got: %v", err)
+			}
+
+			err = secureClient.BulkInstallConnector(context.Background(), "test-token", "connector-profile", "host1.example.com")
+			if tc.expectCallError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tc.expectCallError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// targetHostParam is the GuardAPI parameter asking a Central Manager to run a command on a managed unit
+	targetHostParam = "api_target_host"
+
+	// managedUnitsCommand lists the units managed by a Central Manager
+	managedUnitsCommand = "managed_units"
+)
+
+// ManagedUnit is an appliance registered with a Central Manager
+type ManagedUnit struct {
+	Name   string `json:"name"`
+	Host   string `json:"host"`
+	IP     string `json:"ip"`
+	Online bool   `json:"online"`
+}
+
+// matches reports whether unit identifies u by name, host name or IP address
+func (u ManagedUnit) matches(unit string) bool {
+	for _, id := range []string{u.Name, u.Host, u.IP} {
+		if id != "" && strings.EqualFold(id, unit) {
+			return true
+		}
+	}
+	return false
+}
+
+type targetUnitKey struct{}
+
+// WithTargetUnit routes the GuardAPI commands run with ctx through the Central Manager to the named managed
+// unit, overriding the unit configured with ConfigureTargetUnit. An empty unit runs them on the Central Manager.
+func WithTargetUnit(ctx context.Context, unit string) context.Context {
+	return context.WithValue(ctx, targetUnitKey{}, unit)
+}
+
+// ConfigureTargetUnit routes every GuardAPI command to the named managed unit unless the command context
+// names another one. The client must then be connected to a Central Manager.
+func (c *Client) ConfigureTargetUnit(unit string) {
+	c.targetUnit = unit
+}
+
+// targetUnitFor returns the managed unit commands run with ctx are routed to, empty for the appliance itself
+func (c *Client) targetUnitFor(ctx context.Context) string {
+	if unit, ok := ctx.Value(targetUnitKey{}).(string); ok {
+		return unit
+	}
+	return c.targetUnit
+}
+
+// ManagedUnits lists the units registered with the Central Manager the client is connected to
+func (c *Client) ManagedUnits(ctx context.Context, httpClient *http.Client, accessToken string) ([]ManagedUnit, error) {
+	units, err := executeAs[[]ManagedUnit](ctx, c, httpClient, accessToken, Command{Name: managedUnitsCommand, Idempotent: true, local: true})
+	if err != nil {
+		return nil, fmt.Errorf("error listing managed units: %w", err)
+	}
+
+	return units, nil
+}
+
+// ValidateTargetUnit returns an error when unit is not registered with the Central Manager the client is
+// connected to. Registered units are remembered so that the Central Manager is asked only once per unit.
+func (c *Client) ValidateTargetUnit(ctx context.Context, httpClient *http.Client, accessToken, unit string) error {
+	if err := c.unitsMu.LockContext(ctx); err != nil {
+		return err
+	}
+	defer c.unitsMu.Unlock()
+
+	if _, ok := c.validUnits[unit]; ok {
+		return nil
+	}
+
+	units, err := c.ManagedUnits(ctx, httpClient, accessToken)
+	if err != nil {
+		return fmt.Errorf("could not verify target unit %q, could not list managed units of %s: %w", unit, c.Host, err)
+	}
+
+	var names []string
+	for _, u := range units {
+		if u.matches(unit) {
+			if c.validUnits == nil {
+				c.validUnits = make(map[string]struct{})
+			}
+			c.validUnits[unit] = struct{}{}
+			return nil
+		}
+		names = append(names, u.Name)
+	}
+
+	sort.Strings(names)
+	if len(names) == 0 {
+		return fmt.Errorf("target unit %q is not registered with %s, which manages no units", unit, c.Host)
+	}
+	return fmt.Errorf("target unit %q is not registered with the Central Manager %s, registered units are: %s", unit, c.Host, strings.Join(names, ", "))
+}
+
+// routeToUnit adds the managed unit to the command parameters. JSON object parameters carry it as a field,
+// every other form of command as a query parameter.
+func routeToUnit(method string, cmd Command, query url.Values, unit string) (Command, url.Values, error) {
+	if cmd.Body == nil && cmd.Params != nil && method != http.MethodGet && method != http.MethodHead {
+		data, err := json.Marshal(cmd.Params)
+		if err != nil {
+			return cmd, query, err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var fields map[string]any
+		if err := decoder.Decode(&fields); err == nil && fields != nil {
+			fields[targetHostParam] = unit
+			cmd.Params = fields
+			return cmd, query, nil
+		}
+	}
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set(targetHostParam, unit)
+	return cmd, query, nil
+}
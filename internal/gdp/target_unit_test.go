@@ -0,0 +1,111 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const managedUnitsResponse = `[{"name":"mu1","host":"mu1.example.com","ip":"10.0.0.1","online":true},{"name":"mu2","host":"mu2.example.com","ip":"10.0.0.2"}]`
+
+func TestExecuteTargetUnit(t *testing.T) {
+	var unitRequests atomic.Int32
+	var gotQuery, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/restAPI/managed_units" {
+			unitRequests.Add(1)
+			if r.URL.Query().Has(targetHostParam) {
+				t.Errorf("Expected managed units to be listed on the Central Manager, got query %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(managedUnitsResponse))
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		gotQuery, gotBody = r.URL.RawQuery, string(body)
+	}))
+	defer server.Close()
+
+	client := newGuardAPITestClient(server)
+	client.ConfigureTargetUnit("mu1")
+
+	if _, err := client.Execute(context.Background(), server.Client(), "", Command{Name: "datasource", Params: map[string]string{"name": "db"}}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if gotQuery != "api_target_host=mu1&name=db" {
+		t.Errorf("Expected the unit as query parameter, got %q", gotQuery)
+	}
+
+	ctx := WithTargetUnit(context.Background(), "10.0.0.2")
+	if _, err := client.Execute(ctx, server.Client(), "", Command{Method: http.MethodPost, Name: "datasource", Params: map[string]any{"port": 50000}}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(gotBody), &fields); err != nil {
+		t.Fatalf("Expected a JSON body, got %q", gotBody)
+	}
+	if fields[targetHostParam] != "10.0.0.2" || fields["port"] != float64(50000) {
+		t.Errorf("Expected the unit as body field, got %s", gotBody)
+	}
+	if gotQuery != "" {
+		t.Errorf("Expected no query parameters, got %q", gotQuery)
+	}
+
+	ctx = WithTargetUnit(context.Background(), "")
+	if _, err := client.Execute(ctx, server.Client(), "", Command{Name: "datasource"}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if gotQuery != "" {
+		t.Errorf("Expected the command to run on the Central Manager, got query %q", gotQuery)
+	}
+
+	if got := unitRequests.Load(); got != 2 {
+		t.Errorf("Expected each unit to be verified once, got %d requests", got)
+	}
+}
+
+func TestValidateTargetUnit(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        int
+		units         string
+		unit          string
+		expectedError string
+	}{
+		{name: "Registered by host", status: http.StatusOK, units: managedUnitsResponse, unit: "MU2.example.com"},
+		{name: "Not registered", status: http.StatusOK, units: managedUnitsResponse, unit: "mu3", expectedError: "registered units are: mu1, mu2"},
+		{name: "No units", status: http.StatusOK, units: `[]`, unit: "mu1", expectedError: "manages no units"},
+		{name: "Units not listed", status: http.StatusNotFound, unit: "mu1", expectedError: "could not list managed units"},
+		{name: "Units not readable", status: http.StatusInternalServerError, unit: "mu1", expectedError: "could not list managed units"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.units))
+			}))
+			defer server.Close()
+
+			client := newGuardAPITestClient(server)
+			err := client.ValidateTargetUnit(context.Background(), server.Client(), "", tc.unit)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
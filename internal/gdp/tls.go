@@ -0,0 +1,112 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig describes how the client verifies the appliance certificate and, for mutual TLS,
+// which client certificate it presents. PEM values take precedence over their file counterparts.
+type TLSConfig struct {
+	CAPEM              string
+	CAFile             string
+	ServerName         string
+	MinVersion         string
+	ClientCertPEM      string
+	ClientCertFile     string
+	ClientKeyPEM       string
+	ClientKeyFile      string
+	InsecureSkipVerify bool
+}
+
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion converts a version such as "1.2" or "1.3" to its crypto/tls constant
+func ParseTLSVersion(version string) (uint16, error) {
+	v, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version %q, expected one of 1.2 or 1.3", version)
+	}
+
+	return v, nil
+}
+
+// Build converts the configuration into a crypto/tls configuration. When no certificate authority
+// is given the system roots are used to verify the appliance certificate.
+func (t TLSConfig) Build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+
+	if t.MinVersion != "" {
+		minVersion, err := ParseTLSVersion(t.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.MinVersion = minVersion
+	}
+
+	caPEM, err := pemValue(t.CAPEM, t.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading certificate authority: %w", err)
+	}
+	if caPEM != nil {
+		rootCAs, err := newCertPool(caPEM)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	certPEM, err := pemValue(t.ClientCertPEM, t.ClientCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client certificate: %w", err)
+	}
+	keyPEM, err := pemValue(t.ClientKeyPEM, t.ClientKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client key: %w", err)
+	}
+	if (certPEM == nil) != (keyPEM == nil) {
+		return nil, fmt.Errorf("a client certificate and client key must be configured together")
+	}
+	if certPEM != nil {
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// pemValue returns the inline PEM if set, otherwise the contents of the file, otherwise nil
+func pemValue(inline, file string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if file != "" {
+		return os.ReadFile(file)
+	}
+	return nil, nil
+}
+
+// newCertPool builds a certificate pool containing only the certificates in caPEM
+func newCertPool(caPEM []byte) (*x509.CertPool, error) {
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no PEM encoded certificates found in certificate authority")
+	}
+
+	return rootCAs, nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTLSConfigBuild(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	testCases := []struct {
+		name               string
+		config             TLSConfig
+		expectError        bool
+		expectedMinVersion uint16
+		expectRootCAs      bool
+	}{
+		{
+			name:               "Defaults",
+			config:             TLSConfig{},
+			expectedMinVersion: tls.VersionTLS12,
+		},
+		{
+			name:               "Minimum version 1.3",
+			config:             TLSConfig{MinVersion: "1.3"},
+			expectedMinVersion: tls.VersionTLS13,
+		},
+		{
+			name:        "Unsupported minimum version",
+			config:      TLSConfig{MinVersion: "1.0"},
+			expectError: true,
+		},
+		{
+			name:               "Inline certificate authority",
+			config:             TLSConfig{CAPEM: caPEM},
+			expectedMinVersion: tls.VersionTLS12,
+			expectRootCAs:      true,
+		},
+		{
+			name:        "Invalid certificate authority",
+			config:      TLSConfig{CAPEM: "not a certificate"},
+			expectError: true,
+		},
+		{
+			name:        "Missing certificate authority file",
+			config:      TLSConfig{CAFile: "/does/not/exist.pem"},
+			expectError: true,
+		},
+		{
+			name:        "Client certificate without key",
+			config:      TLSConfig{ClientCertPEM: caPEM},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tlsConfig, err := tc.config.Build()
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			if tlsConfig.MinVersion != tc.expectedMinVersion {
+				t.Errorf("Expected MinVersion %d, got %d", tc.expectedMinVersion, tlsConfig.MinVersion)
+			}
+			if tc.expectRootCAs && tlsConfig.RootCAs == nil {
+				t.Error("Expected RootCAs to be set")
+			}
+		})
+	}
+}
+
+func TestConfigureTLSMutualTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	serverURL := strings.TrimPrefix(server.URL, "https://")
+	urlSplit := strings.Split(serverURL, ":")
+
+	// The test server certificate doubles as the client certificate
+	keyDER, err := x509.MarshalPKCS8PrivateKey(server.TLS.Certificates[0].PrivateKey)
+	if err != nil {
+		t.Fatalf("Error marshaling server key: %v", err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+
+	testCases := []struct {
+		name        string
+		config      TLSConfig
+		expectError bool
+	}{
+		{
+			name:   "Client certificate presented",
+			config: TLSConfig{CAPEM: caPEM, ClientCertPEM: caPEM, ClientKeyPEM: keyPEM},
+		},
+		{
+			name:        "Client certificate missing",
+			config:      TLSConfig{CAPEM: caPEM},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(urlSplit[0], urlSplit[1])
+			if err := client.ConfigureTLS(tc.config); err != nil {
+				t.Fatalf("Expected no error configuring TLS but got: %v", err)
+			}
+
+			secureClient, err := client.NewSecureClient("")
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			err = secureClient.BulkInstallConnector(context.Background(), "test-token", "connector-profile", "host1.example.com")
+			if tc.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
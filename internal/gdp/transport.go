@@ -0,0 +1,170 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	dialTimeout           = 30 * time.Second
+	keepAlive             = 30 * time.Second
+	tlsHandshakeTimeout   = 10 * time.Second
+	idleConnTimeout       = 90 * time.Second
+	maxIdleConns          = 100
+	maxIdleConnsPerHost   = 16
+	expectContinueTimeout = 1 * time.Second
+
+	// DefaultRequestTimeout bounds every call to the appliance, including its retries
+	DefaultRequestTimeout = 5 * time.Minute
+)
+
+// ConfigureRequestTimeout sets how long a single call to the appliance may take, including its retries.
+// Zero disables the limit, leaving calls bounded only by their context.
+func (c *Client) ConfigureRequestTimeout(timeout time.Duration) {
+	c.requestTimeout = timeout
+	c.resetHTTPClients()
+}
+
+// newTransport returns a connection-pooling transport for the given TLS and proxy settings
+func newTransport(tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error)) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: keepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: expectContinueTimeout,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
+// newHTTPClient wraps a pooled transport for the given TLS settings in an http.Client
+// recording or replaying fixtures, limiting concurrency and rate per appliance, adding the configured extra headers,
+// logging requests, retrying transient failures according to the client retry settings
+// and authorizing requests without an explicit access token with the provider credentials
+func (c *Client) newHTTPClient(tlsConfig *tls.Config) *http.Client {
+	var transport http.RoundTripper = newTransport(tlsConfig, c.proxy)
+
+	// Fixtures are recorded closest to the wire so that they hold exactly what was exchanged with the appliance
+	if c.fixtures != nil {
+		transport = c.fixtures.transport(transport)
+	}
+
+	// Every attempt holds a slot of the appliance budget, which is shared across clients for the same host
+	transport = &limitTransport{next: transport, limiter: limiterFor(c.hostKey())}
+
+	// Extra headers are added close to the wire so that token requests carry them too
+	if len(c.headers) > 0 {
+		transport = &headerTransport{next: transport, headers: c.headers}
+	}
+
+	// Every attempt, including token requests and retries, is logged with secrets masked
+	transport = &loggingTransport{next: transport}
+
+	if c.retry.MaxAttempts > 1 {
+		transport = &retryTransport{next: transport, config: c.retry}
+	}
+
+	transport = &authTransport{next: transport, client: c}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   c.requestTimeout,
+	}
+}
+
+// secureHTTPClient returns the shared HTTP client trusting the certificate authority at caCertPath,
+// or the provider level TLS settings when it is empty. Clients are created once and reused so that
+// connections are pooled across every operation.
+func (c *Client) secureHTTPClient(caCertPath string) (*http.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if httpClient, ok := c.httpClients[caCertPath]; ok {
+		return httpClient, nil
+	}
+
+	tlsConfig := c.baseTLSConfig()
+
+	if caCertPath != "" {
+		caPEM, err := os.ReadFile(caCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading certificate authority %s: %w", caCertPath, err)
+		}
+
+		rootCAs, err := newCertPool(caPEM)
+		if err != nil {
+			return nil, fmt.Errorf("error loading certificate authority %s: %w", caCertPath, err)
+		}
+
+		tlsConfig.RootCAs = rootCAs
+		tlsConfig.InsecureSkipVerify = false
+	}
+
+	if c.httpClients == nil {
+		c.httpClients = make(map[string]*http.Client)
+	}
+
+	httpClient := c.newHTTPClient(tlsConfig)
+	c.httpClients[caCertPath] = httpClient
+	return httpClient, nil
+}
+
+// insecureHTTPClient returns the shared HTTP client that skips appliance certificate verification
+func (c *Client) insecureHTTPClient() *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.insecureClient == nil {
+		tlsConfig := c.baseTLSConfig()
+		tlsConfig.InsecureSkipVerify = true
+		c.insecureClient = c.newHTTPClient(tlsConfig)
+	}
+
+	return c.insecureClient
+}
+
+// resetHTTPClients discards the pooled HTTP clients so they are rebuilt with the current settings
+func (c *Client) resetHTTPClients() {
+	c.CloseIdleConnections()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.httpClients = nil
+	c.insecureClient = nil
+}
+
+// closeIdleConnections forwards CloseIdleConnections to round trippers wrapping a pooled transport
+func closeIdleConnections(rt http.RoundTripper) {
+	if closer, ok := rt.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// CloseIdleConnections closes idle connections held by every pooled HTTP client
+func (c *Client) CloseIdleConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, httpClient := range c.httpClients {
+		httpClient.CloseIdleConnections()
+	}
+	if c.insecureClient != nil {
+		c.insecureClient.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPooledHTTPClient(t *testing.T) {
+	var newConnections atomic.Int32
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ID":"1","Message":"Install scheduled"}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConnections.Add(1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	serverURL := strings.TrimPrefix(server.URL, "https://")
+	urlSplit := strings.Split(serverURL, ":")
+	client := NewClient(urlSplit[0], urlSplit[1])
+
+	for i := 0; i < 5; i++ {
+		if err := client.NewInsecureClient().BulkInstallConnector(context.Background(), "test-token", "connector-profile", "host1.example.com"); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	if got := newConnections.Load(); got != 1 {
+		t.Errorf("Expected a single pooled connection, got %d", got)
+	}
+
+	first, err := client.NewSecureClient("")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	second, err := client.NewSecureClient("")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if first.httpClient != second.httpClient {
+		t.Error("Expected secure clients to share the pooled HTTP client")
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a hung appliance
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	testCases := []struct {
+		name           string
+		requestTimeout time.Duration
+		ctxTimeout     time.Duration
+	}{
+		{name: "Provider request timeout", requestTimeout: 50 * time.Millisecond, ctxTimeout: time.Minute},
+		{name: "Operation context deadline", requestTimeout: 0, ctxTimeout: 50 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(server)
+			client.ConfigureRetry(RetryConfig{MaxAttempts: 1})
+			client.ConfigureRequestTimeout(tc.requestTimeout)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.ctxTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := client.NewInsecureClient().BulkInstallConnector(ctx, "test-token", "connector-profile", "host1.example.com")
+			if err == nil {
+				t.Fatal("Expected error but got nil")
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Expected the hung call to be abandoned, took %s", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// streamBody is a request body read from its source while it is sent. Its size is known up front so that it
+// is not sent with chunked encoding, and it can be reopened when the request is retried.
+type streamBody interface {
+	io.ReadCloser
+	Len() int64
+	Reopen() (io.ReadCloser, error)
+}
+
+// formField is a plain multipart/form-data field
+type formField struct {
+	Name  string
+	Value string
+}
+
+// fileUpload is a multipart/form-data body streaming a file from disk, so that large files are never held in
+// memory and stop being read as soon as the request context is done
+type fileUpload struct {
+	ctx         context.Context
+	path        string
+	size        int64
+	contentType string
+	// head holds the form data sent before the file content, tail the form data sent after it
+	head []byte
+	tail []byte
+
+	file   *os.File
+	reader io.Reader
+}
+
+var _ streamBody = &fileUpload{}
+
+// newFileUpload prepares a multipart/form-data body with the file at path as fileField, followed by fields
+func newFileUpload(ctx context.Context, fileField, path string, fields ...formField) (*fileUpload, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file %s: %w", path, err)
+	}
+	info, err := file.Stat()
+	file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", path, err)
+	}
+
+	// The form is written around an empty file part, whose content is streamed from disk later
+	form := &bytes.Buffer{}
+	writer := multipart.NewWriter(form)
+	if _, err := writer.CreateFormFile(fileField, filepath.Base(path)); err != nil {
+		return nil, fmt.Errorf("error creating form file: %w", err)
+	}
+	headLen := form.Len()
+
+	for _, field := range fields {
+		if err := writer.WriteField(field.Name, field.Value); err != nil {
+			return nil, fmt.Errorf("error writing %s field: %w", field.Name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("error closing multipart writer: %w", err)
+	}
+
+	return &fileUpload{
+		ctx:         ctx,
+		path:        path,
+		size:        info.Size(),
+		contentType: writer.FormDataContentType(),
+		head:        form.Bytes()[:headLen],
+		tail:        form.Bytes()[headLen:],
+	}, nil
+}
+
+// ContentType returns the multipart/form-data content type, including the boundary
+func (u *fileUpload) ContentType() string {
+	return u.contentType
+}
+
+// Len returns the size of the body
+func (u *fileUpload) Len() int64 {
+	return int64(len(u.head)) + u.size + int64(len(u.tail))
+}
+
+// Read opens the file on first use and fails once the upload context is done
+func (u *fileUpload) Read(p []byte) (int, error) {
+	if err := u.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if u.reader == nil {
+		file, err := os.Open(u.path)
+		if err != nil {
+			return 0, fmt.Errorf("error opening file %s: %w", u.path, err)
+		}
+		u.file = file
+		// A file that grew since the upload was prepared must not overflow the announced length
+		u.reader = io.MultiReader(bytes.NewReader(u.head), io.LimitReader(file, u.size), bytes.NewReader(u.tail))
+	}
+
+	return u.reader.Read(p)
+}
+
+// Close closes the file, if it was opened
+func (u *fileUpload) Close() error {
+	if u.file == nil {
+		return nil
+	}
+	return u.file.Close()
+}
+
+// Reopen returns a new body sending the same form from the start
+func (u *fileUpload) Reopen() (io.ReadCloser, error) {
+	return &fileUpload{
+		ctx:         u.ctx,
+		path:        u.path,
+		size:        u.size,
+		contentType: u.contentType,
+		head:        u.head,
+		tail:        u.tail,
+	}, nil
+}
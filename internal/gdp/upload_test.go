@@ -0,0 +1,132 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileUpload(t *testing.T) {
+	content := strings.Repeat("profile;", 100000)
+	pathToFile := filepath.Join(t.TempDir(), "profiles.csv")
+	if err := os.WriteFile(pathToFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var contentLength int64
+	var gotFile, gotMode string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Expected a multipart form, got error: %v", err)
+			return
+		}
+		file, header, err := r.FormFile("path")
+		if err != nil {
+			t.Errorf("Expected the file in the path field, got error: %v", err)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		gotFile, gotMode = header.Filename+":"+string(data), r.FormValue("updateMode")
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	upload, err := newFileUpload(ctx, "path", pathToFile, formField{Name: "updateMode", Value: "true"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	client := newGuardAPITestClient(server)
+	if _, err := client.Execute(ctx, server.Client(), "", Command{Method: http.MethodPost, Name: "importProfilesFromFile", Body: upload, ContentType: upload.ContentType()}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if contentLength != upload.Len() {
+		t.Errorf("Expected Content-Length %d, got %d", upload.Len(), contentLength)
+	}
+	if gotFile != "profiles.csv:"+content {
+		t.Errorf("Unexpected file received, %d bytes", len(gotFile))
+	}
+	if gotMode != "true" {
+		t.Errorf("Expected updateMode true, got %q", gotMode)
+	}
+
+	// A reopened upload sends the same body
+	reopened, _ := upload.Reopen()
+	defer reopened.Close()
+	data, err := io.ReadAll(reopened)
+	if err != nil || int64(len(data)) != upload.Len() {
+		t.Errorf("Expected the reopened body to hold %d bytes, got %d: %v", upload.Len(), len(data), err)
+	}
+}
+
+func TestFileUploadMissingFile(t *testing.T) {
+	_, err := newFileUpload(context.Background(), "path", filepath.Join(t.TempDir(), "missing.csv"))
+	if err == nil || !strings.Contains(err.Error(), "error opening file") {
+		t.Errorf("Expected an error opening the file, got %v", err)
+	}
+}
+
+func TestFileUploadCanceled(t *testing.T) {
+	pathToFile := filepath.Join(t.TempDir(), "profiles.csv")
+	if err := os.WriteFile(pathToFile, []byte("profile"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	upload, err := newFileUpload(ctx, "path", pathToFile)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	cancel()
+
+	if _, err := io.ReadAll(upload); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected reading to stop with context.Canceled, got %v", err)
+	}
+}
+
+func TestExecuteCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := newGuardAPITestClient(server)
+	_, err := client.Execute(ctx, server.Client(), "", Command{Method: http.MethodPost, Name: "bulkInstall"})
+	if !IsCanceled(err) {
+		t.Errorf("Expected a cancellation error, got %v", err)
+	}
+}
+
+func TestProviderTokenCanceledWhileWaiting(t *testing.T) {
+	client := NewClient("localhost", "8443")
+	client.ConfigureCredentials(Credentials{ClientID: "client", Username: "user", Password: "password"})
+
+	// Another operation holds the token lock while it requests a token
+	client.tokenMu.Lock()
+	defer client.tokenMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.providerToken(ctx, http.DefaultClient); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected waiting for the token to stop at the deadline, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// versionCommand is the GuardAPI command reporting the appliance version and patch level
+const versionCommand = "guardium_version"
+
+// ErrVersionUnknown is returned when the appliance does not report its version, which is the case for
+// releases older than the version command
+var ErrVersionUnknown = errors.New("the appliance does not report its version")
+
+// ApplianceVersion is the Guardium release and patch level of an appliance
+type ApplianceVersion struct {
+	Major int
+	Minor int
+	// Patch is the highest installed patch, 0 when none is installed
+	Patch int
+	// Build is the build identifier reported by the appliance, if any
+	Build string
+}
+
+var (
+	// versionPattern matches versions such as "12.1", "V12.1.0" or "12.0p540"
+	versionPattern = regexp.MustCompile(`(?i)(\d+)\.(\d+)(?:\.\d+)*(?:\s*p(?:atch)?\s*(\d+))?`)
+	// patchPattern matches the trailing number of patch levels such as "540", "p540" or "12.0p540"
+	patchPattern = regexp.MustCompile(`(\d+)\s*$`)
+)
+
+// ParseApplianceVersion parses the version and patch level reported by an appliance. The patch may also be
+// part of version, as in "12.0p540".
+func ParseApplianceVersion(version, patch string) (ApplianceVersion, error) {
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return ApplianceVersion{}, fmt.Errorf("invalid Guardium version %q", version)
+	}
+
+	v := ApplianceVersion{}
+	v.Major, _ = strconv.Atoi(match[1])
+	v.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		v.Patch, _ = strconv.Atoi(match[3])
+	}
+
+	if patch = strings.TrimSpace(patch); patch != "" {
+		patchMatch := patchPattern.FindStringSubmatch(patch)
+		if patchMatch == nil {
+			return ApplianceVersion{}, fmt.Errorf("invalid Guardium patch level %q", patch)
+		}
+		v.Patch, _ = strconv.Atoi(patchMatch[1])
+	}
+
+	return v, nil
+}
+
+// String returns the version as Guardium documents it, e.g. "12.1 patch 100"
+func (v ApplianceVersion) String() string {
+	if v.Patch == 0 {
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	return fmt.Sprintf("%d.%d patch %d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 when v is older than, the same as or newer than other
+func (v ApplianceVersion) Compare(other ApplianceVersion) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		switch {
+		case d < 0:
+			return -1
+		case d > 0:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Capability is an API feature only available from a given Guardium version on
+type Capability struct {
+	// Name describes the feature in error messages
+	Name string
+	// MinVersion is the oldest version providing the feature
+	MinVersion ApplianceVersion
+}
+
+// Supports reports whether an appliance running v provides c
+func (v ApplianceVersion) Supports(c Capability) bool {
+	return v.Compare(c.MinVersion) >= 0
+}
+
+// UnsupportedError is returned when an operation needs a newer appliance
+type UnsupportedError struct {
+	Capability Capability
+	Version    ApplianceVersion
+	// Unit is the managed unit the operation was routed to, empty for the appliance itself
+	Unit string
+}
+
+func (e *UnsupportedError) Error() string {
+	appliance := "the appliance"
+	if e.Unit != "" {
+		appliance = fmt.Sprintf("the managed unit %s", e.Unit)
+	}
+	return fmt.Sprintf("%s requires Guardium %s or newer, %s runs Guardium %s", e.Capability.Name, e.Capability.MinVersion, appliance, e.Version)
+}
+
+// Require returns an *UnsupportedError when an appliance running v does not provide c
+func (v ApplianceVersion) Require(c Capability) error {
+	if v.Supports(c) {
+		return nil
+	}
+	return &UnsupportedError{Capability: c, Version: v}
+}
+
+// IsUnsupported reports whether err is caused by an appliance too old for the operation
+func IsUnsupported(err error) bool {
+	var unsupported *UnsupportedError
+	return errors.As(err, &unsupported)
+}
+
+// versionResponse is the body of the version command. Field matching is case-insensitive and the patch level
+// may be reported as a number or a string.
+type versionResponse struct {
+	Version string          `json:"version"`
+	Patch   json.RawMessage `json:"patch"`
+	Build   string          `json:"build"`
+}
+
+// ApplianceVersion returns the version of the appliance the client is connected to, a Central Manager rather
+// than the managed unit commands are routed to. It is requested once and then cached for the lifetime of c,
+// including ErrVersionUnknown for appliances that do not report it.
+func (c *Client) ApplianceVersion(ctx context.Context, httpClient *http.Client, accessToken string) (ApplianceVersion, error) {
+	return c.unitVersion(ctx, httpClient, accessToken, "")
+}
+
+// unitVersion returns the version of the managed unit named unit, through the Central Manager, or of the
+// appliance itself when unit is empty. Each version is cached like ApplianceVersion.
+func (c *Client) unitVersion(ctx context.Context, httpClient *http.Client, accessToken, unit string) (ApplianceVersion, error) {
+	if err := c.versionMu.LockContext(ctx); err != nil {
+		return ApplianceVersion{}, err
+	}
+	defer c.versionMu.Unlock()
+
+	if version, ok := c.versions[unit]; ok {
+		return version, nil
+	}
+	if err := c.versionErrs[unit]; err != nil {
+		return ApplianceVersion{}, err
+	}
+
+	cmd := Command{Name: versionCommand, Idempotent: true, local: unit == ""}
+	resp, err := executeAs[versionResponse](WithTargetUnit(ctx, unit), c, httpClient, accessToken, cmd)
+	if err != nil {
+		if IsNotFound(err) {
+			if c.versionErrs == nil {
+				c.versionErrs = make(map[string]error)
+			}
+			c.versionErrs[unit] = fmt.Errorf("%w: %w", ErrVersionUnknown, err)
+			return ApplianceVersion{}, c.versionErrs[unit]
+		}
+		if unit != "" {
+			return ApplianceVersion{}, fmt.Errorf("error getting version of managed unit %s: %w", unit, err)
+		}
+		return ApplianceVersion{}, fmt.Errorf("error getting appliance version: %w", err)
+	}
+
+	version, err := ParseApplianceVersion(resp.Version, rawString(resp.Patch))
+	if err != nil {
+		return ApplianceVersion{}, err
+	}
+	version.Build = resp.Build
+
+	tflog.Debug(ctx, "detected appliance version", map[string]any{"version": version.String(), "build": version.Build, "target_unit": unit})
+	if c.versions == nil {
+		c.versions = make(map[string]ApplianceVersion)
+	}
+	c.versions[unit] = version
+	return version, nil
+}
+
+// RequireCapability fails with an *UnsupportedError when the appliance commands run with ctx are sent to, the
+// managed unit they are routed to if any, is known to be too old for capability. Appliances that do not report
+// their version are given the benefit of the doubt, the call itself then reports any incompatibility.
+func (c *Client) RequireCapability(ctx context.Context, httpClient *http.Client, accessToken string, capability Capability) error {
+	unit := c.targetUnitFor(ctx)
+	version, err := c.unitVersion(ctx, httpClient, accessToken, unit)
+	if errors.Is(err, ErrVersionUnknown) {
+		tflog.Debug(ctx, "appliance version unknown, assuming support", map[string]any{"capability": capability.Name, "target_unit": unit})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !version.Supports(capability) {
+		return &UnsupportedError{Capability: capability, Version: version, Unit: unit}
+	}
+	return nil
+}
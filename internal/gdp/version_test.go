@@ -0,0 +1,177 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gdp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseApplianceVersion(t *testing.T) {
+	testCases := []struct {
+		version     string
+		patch       string
+		expected    ApplianceVersion
+		expectError bool
+	}{
+		{version: "12.1", expected: ApplianceVersion{Major: 12, Minor: 1}},
+		{version: "V12.1.0", patch: "100", expected: ApplianceVersion{Major: 12, Minor: 1, Patch: 100}},
+		{version: "12.0p540", expected: ApplianceVersion{Major: 12, Minor: 0, Patch: 540}},
+		{version: "11.5", patch: "p520", expected: ApplianceVersion{Major: 11, Minor: 5, Patch: 520}},
+		{version: "12.0", patch: "12.0p45", expected: ApplianceVersion{Major: 12, Minor: 0, Patch: 45}},
+		{version: "unknown", expectError: true},
+		{version: "12.1", patch: "latest", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version+" "+tc.patch, func(t *testing.T) {
+			version, err := ParseApplianceVersion(tc.version, tc.patch)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got %v", version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if version != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, version)
+			}
+		})
+	}
+}
+
+func TestApplianceVersionRequire(t *testing.T) {
+	capability := Capability{Name: "Uploading", MinVersion: ApplianceVersion{Major: 12, Minor: 1, Patch: 100}}
+
+	for _, version := range []ApplianceVersion{{Major: 12, Minor: 1, Patch: 100}, {Major: 12, Minor: 2}, {Major: 13}} {
+		if err := version.Require(capability); err != nil {
+			t.Errorf("Expected %s to be supported, got %v", version, err)
+		}
+	}
+
+	err := ApplianceVersion{Major: 12, Minor: 1, Patch: 50}.Require(capability)
+	if !IsUnsupported(err) {
+		t.Fatalf("Expected an UnsupportedError, got %v", err)
+	}
+	expected := "Uploading requires Guardium 12.1 patch 100 or newer, the appliance runs Guardium 12.1 patch 50"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestClientApplianceVersion(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/restAPI/guardium_version" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"Version":"12.1","Patch":100,"Build":"12.1.0.100"}`))
+	}))
+	defer server.Close()
+
+	client := newGuardAPITestClient(server)
+	for range 3 {
+		version, err := client.ApplianceVersion(context.Background(), server.Client(), "")
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if version != (ApplianceVersion{Major: 12, Minor: 1, Patch: 100, Build: "12.1.0.100"}) {
+			t.Errorf("Unexpected version %+v", version)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected the version to be requested once, got %d requests", got)
+	}
+}
+
+func TestImportProfilesFromFileVersion(t *testing.T) {
+	pathToFile := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(pathToFile, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		version       string
+		versionStatus int
+	}{
+		{name: "Known version", version: `{"version":"12.1","patch":"p100"}`, versionStatus: http.StatusOK},
+		{name: "Old version", version: `{"version":"12.0","patch":"p540"}`, versionStatus: http.StatusOK},
+		{name: "Unknown version", versionStatus: http.StatusNotFound},
+		{name: "Unreadable version", versionStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var uploaded atomic.Bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/restAPI/guardium_version":
+					w.WriteHeader(tc.versionStatus)
+					w.Write([]byte(tc.version))
+				case "/restAPI/importProfilesFromFile":
+					uploaded.Store(true)
+				}
+			}))
+			defer server.Close()
+
+			client := newGuardAPITestClient(server)
+			err := client.ImportProfilesFromFile(context.Background(), server.Client(), "", pathToFile, false)
+
+			// The version is only logged, uploads are never refused because of it
+			if !uploaded.Load() {
+				t.Error("Expected the file to be uploaded")
+			}
+			if err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRequireCapabilityTargetUnit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/restAPI/managed_units" {
+			w.Write([]byte(managedUnitsResponse))
+			return
+		}
+		// The Central Manager is upgraded before its managed units
+		switch r.URL.Query().Get(targetHostParam) {
+		case "":
+			w.Write([]byte(`{"version":"12.1","patch":"p100"}`))
+		case "mu1":
+			w.Write([]byte(`{"version":"12.0","patch":"p540"}`))
+		default:
+			w.Write([]byte(`{"version":"12.2","patch":"p10"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newGuardAPITestClient(server)
+	client.ConfigureTargetUnit("mu1")
+	ctx := context.Background()
+	capability := Capability{Name: "Uploading", MinVersion: ApplianceVersion{Major: 12, Minor: 1, Patch: 100}}
+
+	var unsupported *UnsupportedError
+	err := client.RequireCapability(ctx, server.Client(), "", capability)
+	if !errors.As(err, &unsupported) || unsupported.Unit != "mu1" || !strings.Contains(err.Error(), "managed unit mu1") {
+		t.Errorf("Expected the version of mu1 to be unsupported, got %v", err)
+	}
+	if err := client.RequireCapability(WithTargetUnit(ctx, "mu2"), server.Client(), "", capability); err != nil {
+		t.Errorf("Expected the version of mu2 to be supported, got %v", err)
+	}
+	if err := client.RequireCapability(WithTargetUnit(ctx, ""), server.Client(), "", capability); err != nil {
+		t.Errorf("Expected the version of the Central Manager to be supported, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource              = &applianceVersionDataSource{}
+	_ datasource.DataSourceWithConfigure = &applianceVersionDataSource{}
+)
+
+// NewApplianceVersionDataSource is a helper function to simplify the provider implementation.
+func NewApplianceVersionDataSource() datasource.DataSource {
+	return &applianceVersionDataSource{}
+}
+
+// applianceVersionDataSource reports the Guardium version of the appliance
+type applianceVersionDataSource struct {
+	client gdp.API
+}
+
+// applianceVersionDataSourceModel maps the data source schema data.
+type applianceVersionDataSourceModel struct {
+	AccessToken types.String `tfsdk:"access_token"`
+	CAPath      types.String `tfsdk:"ca_path"`
+	Version     types.String `tfsdk:"version"`
+	Major       types.Int64  `tfsdk:"major"`
+	Minor       types.Int64  `tfsdk:"minor"`
+	Patch       types.Int64  `tfsdk:"patch"`
+	Build       types.String `tfsdk:"build"`
+}
+
+func (d *applianceVersionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appliance_version"
+}
+
+func (d *applianceVersionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Reports the Guardium version and patch level of the appliance. The version is detected once per Terraform run",
+
+		Attributes: map[string]schema.Attribute{
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"ca_path": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
+				Optional:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Version and patch level, e.g. `12.1 patch 100`",
+				Computed:            true,
+			},
+			"major": schema.Int64Attribute{
+				MarkdownDescription: "Major version, e.g. `12`",
+				Computed:            true,
+			},
+			"minor": schema.Int64Attribute{
+				MarkdownDescription: "Minor version, e.g. `1`",
+				Computed:            true,
+			},
+			"patch": schema.Int64Attribute{
+				MarkdownDescription: "Highest installed patch, `0` when none is installed",
+				Computed:            true,
+			},
+			"build": schema.StringAttribute{
+				MarkdownDescription: "Build identifier reported by the appliance, if any",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *applianceVersionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(gdp.API)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected gdp.API, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *applianceVersionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data applianceVersionDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, err := newGDPAPI(d.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	version, err := c.ApplianceVersion(ctx, data.AccessToken.ValueString())
+	if err != nil {
+		detail := fmt.Sprintf("Failed to detect the appliance version: %s.", err.Error())
+		if errors.Is(err, gdp.ErrVersionUnknown) {
+			detail += " The appliance may be older than the releases that report their version."
+		}
+		addClientError(&resp.Diagnostics, "Failed to detect appliance version", detail, err)
+		return
+	}
+
+	data.Version = types.StringValue(version.String())
+	data.Major = types.Int64Value(int64(version.Major))
+	data.Minor = types.Int64Value(int64(version.Minor))
+	data.Patch = types.Int64Value(int64(version.Patch))
+	data.Build = types.StringValue(version.Build)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+func TestAccApplianceVersionDataSource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: appliance.config(`
+data "guardium-data-protection_appliance_version" "test" {
+  ca_path = %q
+}
+`, appliance.CAFile),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.guardium-data-protection_appliance_version.test", "major", "12"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_appliance_version.test", "minor", "1"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_appliance_version.test", "patch", "100"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccApplianceVersionDataSourceFixtures(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+	config := appliance.config(`
+data "guardium-data-protection_appliance_version" "test" {}
+`)
+	checkVersion := resource.TestCheckResourceAttr("data.guardium-data-protection_appliance_version.test", "version", "12.1 patch 100")
+	fixtures := filepath.Join(t.TempDir(), "fixtures.json")
+
+	t.Setenv(envFixturesMode, string(gdp.FixtureModeRecord))
+	t.Setenv(envFixturesFile, fixtures)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    []resource.TestStep{{Config: config, Check: checkVersion}},
+	})
+
+	// The appliance no longer reports its version, the recorded one is replayed
+	appliance.Version = ""
+	t.Setenv(envFixturesMode, string(gdp.FixtureModeReplay))
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    []resource.TestStep{{Config: config, Check: checkVersion}},
+	})
+}
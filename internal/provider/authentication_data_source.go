@@ -24,7 +24,7 @@ func NewAuthenticationDataSource() datasource.DataSource {
 
 // AuthenticationDataSource defines the data source implementation.
 type AuthenticationDataSource struct {
-	client *gdp.Client
+	client gdp.API
 }
 
 // AuthenticationDataSourceModel describes the data source data model.
@@ -33,6 +33,9 @@ type AuthenticationDataSourceModel struct {
 	ClientID     types.String `tfsdk:"client_id"`
 	Username     types.String `tfsdk:"username"`
 	Password     types.String `tfsdk:"password"`
+	GrantType    types.String `tfsdk:"grant_type"`
+	RefreshToken types.String `tfsdk:"refresh_token"`
+	ClientAuth   types.String `tfsdk:"client_auth"`
 	CAPath       types.String `tfsdk:"ca_path"`
 	AccessToken  types.String `tfsdk:"access_token"`
 }
@@ -51,26 +54,39 @@ func (d *AuthenticationDataSource) Schema(ctx context.Context, req datasource.Sc
 		// Attributes are the hcl implementation of the above AuthenticationDataSourceModel
 		Attributes: map[string]schema.Attribute{
 			"client_secret": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection Client Secret",
-				Required:            true,
+				MarkdownDescription: "Guardium Data Protection Client Secret, required by the `password` and `client_credentials` grants",
+				Optional:            true,
 				Sensitive:           true,
 				// Sensitive means values will still show up in the state, but will be protected at cli logs
 			},
 			"client_id": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection Client Secret",
+				MarkdownDescription: "Guardium Data Protection Client ID",
 				Required:            true,
 			},
 			"username": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection username",
-				Required:            true,
+				MarkdownDescription: "Guardium Data Protection username, required by the `password` grant",
+				Optional:            true,
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection password",
-				Required:            true,
+				MarkdownDescription: "Guardium Data Protection password, required by the `password` grant",
+				Optional:            true,
 				Sensitive:           true,
 			},
+			"grant_type": schema.StringAttribute{
+				MarkdownDescription: "OAuth grant used to obtain the access token: `password`, `client_credentials` or `refresh_token`. Defaults to `password`",
+				Optional:            true,
+			},
+			"refresh_token": schema.StringAttribute{
+				MarkdownDescription: "OAuth refresh token, required by the `refresh_token` grant",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_auth": schema.StringAttribute{
+				MarkdownDescription: "How the OAuth client authenticates to the token endpoint, either `body` or `basic`. Defaults to `body`",
+				Optional:            true,
+			},
 			"ca_path": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection certificate authority",
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
 				Optional:            true,
 			},
 			"access_token": schema.StringAttribute{
@@ -88,11 +104,11 @@ func (d *AuthenticationDataSource) Configure(ctx context.Context, req datasource
 		return
 	}
 
-	client, ok := req.ProviderData.(*gdp.Client)
+	client, ok := req.ProviderData.(gdp.API)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *gdp.Client, got: %T.", req.ProviderData),
+			fmt.Sprintf("Expected gdp.API, got: %T.", req.ProviderData),
 		)
 
 		return
@@ -114,22 +130,39 @@ func (d *AuthenticationDataSource) Read(ctx context.Context, req datasource.Read
 		return
 	}
 
-	var (
-		accessToken string
-		err         error
-	)
-
-	if data.CAPath.IsNull() {
-		accessToken, err = d.client.NewInsecureClient().GenerateAccessToken(ctx, data.ClientSecret.ValueString(), data.Username.ValueString(), data.Password.ValueString(), data.ClientID.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to retrieve access token",
-				fmt.Sprintf("Failed to retrieve access token: %s.", err.Error()),
-			)
-			return
-		}
+	c, err := newGDPAPI(d.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	credentials := gdp.Credentials{
+		GrantType:    data.GrantType.ValueString(),
+		ClientID:     data.ClientID.ValueString(),
+		ClientSecret: data.ClientSecret.ValueString(),
+		Username:     data.Username.ValueString(),
+		Password:     data.Password.ValueString(),
+		RefreshToken: data.RefreshToken.ValueString(),
+		ClientAuth:   data.ClientAuth.ValueString(),
+	}
+	if err := credentials.Validate(); err != nil {
+		resp.Diagnostics.AddError("Invalid credentials", fmt.Sprintf("%s.", err))
+		return
+	}
+
+	accessToken, err := c.GenerateAccessToken(ctx, credentials)
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Failed to retrieve access token",
+			fmt.Sprintf("Failed to retrieve access token: %s.", err.Error()),
+			err,
+		)
+		return
 	}
-	tflog.Info(ctx, accessToken)
 	data.AccessToken = types.StringValue(accessToken)
 	resp.State.Set(ctx, data)
 }
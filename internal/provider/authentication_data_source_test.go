@@ -0,0 +1,61 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAuthenticationDataSource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: appliance.config(`
+data "guardium-data-protection_authentication" "test" {
+  client_id     = %q
+  client_secret = %q
+  username      = %q
+  password      = %q
+  ca_path       = %q
+}
+`, appliance.ClientID, appliance.ClientSecret, appliance.Username, appliance.Password, appliance.CAFile),
+				Check: resource.TestCheckResourceAttrSet("data.guardium-data-protection_authentication.test", "access_token"),
+			},
+			{
+				Config: appliance.config(`
+data "guardium-data-protection_authentication" "test" {
+  client_id     = %q
+  client_secret = %q
+  username      = %q
+  password      = "wrong"
+  ca_path       = %q
+}
+`, appliance.ClientID, appliance.ClientSecret, appliance.Username, appliance.CAFile),
+				ExpectError: regexp.MustCompile(`Failed to retrieve access token`),
+			},
+			{
+				// Without preflight the provider credentials are only used by calls without explicit ones
+				Config: strings.Replace(appliance.config(`
+data "guardium-data-protection_authentication" "test" {
+  client_id     = %q
+  client_secret = %q
+  username      = %q
+  password      = %q
+  ca_path       = %q
+}
+`, appliance.ClientID, appliance.ClientSecret, appliance.Username, appliance.Password, appliance.CAFile),
+					fmt.Sprintf("password      = %q", appliance.Password), `password      = "wrong"`, 1),
+				Check: resource.TestCheckResourceAttrSet("data.guardium-data-protection_authentication.test", "access_token"),
+			},
+		},
+	})
+}
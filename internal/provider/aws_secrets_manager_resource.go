@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -16,22 +18,30 @@ import (
 	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
 )
 
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &AWSSecretsManagerResource{}
+	_ resource.ResourceWithConfigure   = &AWSSecretsManagerResource{}
+	_ resource.ResourceWithImportState = &AWSSecretsManagerResource{}
+)
+
 // AWSSecretsManagerResource defines the resource implementation
 type AWSSecretsManagerResource struct {
-	client *gdp.Client
+	client gdp.API
 }
 
 // AWSSecretsManagerResourceModel describes the resource data model
 type AWSSecretsManagerResourceModel struct {
-	AccessToken       types.String `tfsdk:"access_token"`
-	Name              types.String `tfsdk:"name"`
-	AuthType          types.String `tfsdk:"auth_type"`
-	AccessKeyID       types.String `tfsdk:"access_key_id"`
-	SecretAccessKey   types.String `tfsdk:"secret_access_key"`
-	SecretKeyUsername types.String `tfsdk:"secret_key_username"`
-	SecretKeyPassword types.String `tfsdk:"secret_key_password"`
-	ID                types.String `tfsdk:"id"`
-	CaPath            types.String `tfsdk:"ca_path"`
+	AccessToken       types.String   `tfsdk:"access_token"`
+	Name              types.String   `tfsdk:"name"`
+	AuthType          types.String   `tfsdk:"auth_type"`
+	AccessKeyID       types.String   `tfsdk:"access_key_id"`
+	SecretAccessKey   types.String   `tfsdk:"secret_access_key"`
+	SecretKeyUsername types.String   `tfsdk:"secret_key_username"`
+	SecretKeyPassword types.String   `tfsdk:"secret_key_password"`
+	ID                types.String   `tfsdk:"id"`
+	CaPath            types.String   `tfsdk:"ca_path"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
 }
 
 func NewAWSSecretsManagerResource() resource.Resource {
@@ -44,13 +54,14 @@ func (r *AWSSecretsManagerResource) Metadata(_ context.Context, req resource.Met
 }
 
 // Schema defines the schema for the resource
-func (r *AWSSecretsManagerResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *AWSSecretsManagerResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "AWS Secrets Manager configuration for Guardium Data Protection",
+		MarkdownDescription: "AWS Secrets Manager configuration for Guardium Data Protection. Configurations are imported by name, " +
+			"the secrets are not read back from the appliance and must be set in the configuration",
 		Attributes: map[string]schema.Attribute{
 			"access_token": schema.StringAttribute{
-				MarkdownDescription: "Access token for authentication",
-				Required:            true,
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
 				Sensitive:           true,
 			},
 			"name": schema.StringAttribute{
@@ -81,7 +92,7 @@ func (r *AWSSecretsManagerResource) Schema(_ context.Context, _ resource.SchemaR
 				Sensitive:           true,
 			},
 			"ca_path": schema.StringAttribute{
-				MarkdownDescription: "Path to CA certificate",
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
 				Optional:            true,
 			},
 			"id": schema.StringAttribute{
@@ -92,6 +103,9 @@ func (r *AWSSecretsManagerResource) Schema(_ context.Context, _ resource.SchemaR
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -101,9 +115,9 @@ func (r *AWSSecretsManagerResource) Configure(ctx context.Context, req resource.
 		return
 	}
 
-	client, ok := req.ProviderData.(*gdp.Client)
+	client, ok := req.ProviderData.(gdp.API)
 	if !ok {
-		resp.Diagnostics.AddError("Unexpected Resource Configure Type", fmt.Sprintf("Expected *gdp.Client, got: %T", req.ProviderData))
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type", fmt.Sprintf("Expected gdp.API, got: %T", req.ProviderData))
 		return
 	}
 
@@ -119,6 +133,12 @@ func (r *AWSSecretsManagerResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Creating AWS Secrets Manager configuration")
 
 	config := gdp.NewAWSSecretsManagerConfig(
@@ -130,28 +150,30 @@ func (r *AWSSecretsManagerResource) Create(ctx context.Context, req resource.Cre
 		data.SecretKeyPassword.ValueString(),
 	)
 
-	if data.CaPath.IsNull() {
-		c := r.client.NewInsecureClient()
+	c, err := newGDPAPI(r.client, data.CaPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority", fmt.Sprintf("Could not load certificate authority: %s", err))
+		return
+	}
+
+	// Check if a configuration with this name already exists
+	existingConfig, err := c.GetAWSSecretsManager(ctx, data.AccessToken.ValueString(), data.Name.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Error checking for existing AWS Secrets Manager configuration", fmt.Sprintf("Could not check for existing configuration: %s", err), err)
+		return
+	}
 
-		// Check if a configuration with this name already exists
-		existingConfig, err := c.GetAWSSecretsManager(ctx, data.AccessToken.ValueString(), data.Name.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("Error checking for existing AWS Secrets Manager configuration", fmt.Sprintf("Could not check for existing configuration: %s", err))
+	if existingConfig != nil {
+		// Configuration already exists, update it
+		if err := c.UpdateAWSSecretsManager(ctx, data.AccessToken.ValueString(), config); err != nil {
+			addClientError(&resp.Diagnostics, "Error updating existing AWS Secrets Manager configuration", fmt.Sprintf("Could not update existing configuration: %s", err), err)
 			return
 		}
-
-		if existingConfig != nil {
-			// Configuration already exists, update it
-			if err := c.UpdateAWSSecretsManager(ctx, data.AccessToken.ValueString(), config); err != nil {
-				resp.Diagnostics.AddError("Error updating existing AWS Secrets Manager configuration", fmt.Sprintf("Could not update existing configuration: %s", err))
-				return
-			}
-		} else {
-			// Configuration doesn't exist, create it
-			if err := c.CreateAWSSecretsManager(ctx, data.AccessToken.ValueString(), config); err != nil {
-				resp.Diagnostics.AddError("Error creating AWS Secrets Manager configuration", fmt.Sprintf("Could not create configuration: %s", err))
-				return
-			}
+	} else {
+		// Configuration doesn't exist, create it
+		if err := c.CreateAWSSecretsManager(ctx, data.AccessToken.ValueString(), config); err != nil {
+			addClientError(&resp.Diagnostics, "Error creating AWS Secrets Manager configuration", fmt.Sprintf("Could not create configuration: %s", err), err)
+			return
 		}
 	}
 
@@ -172,28 +194,38 @@ func (r *AWSSecretsManagerResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading AWS Secrets Manager configuration")
 
-	if data.CaPath.IsNull() {
-		c := r.client.NewInsecureClient()
-		config, err := c.GetAWSSecretsManager(ctx, data.AccessToken.ValueString(), data.Name.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("Error reading AWS Secrets Manager configuration", fmt.Sprintf("Could not read AWS Secrets Manager configuration: %s", err))
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CaPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority", fmt.Sprintf("Could not load certificate authority: %s", err))
+		return
+	}
 
-		// If the resource doesn't exist, remove it from state
-		if config == nil {
-			resp.State.RemoveResource(ctx)
-			return
-		}
+	config, err := c.GetAWSSecretsManager(ctx, data.AccessToken.ValueString(), data.Name.ValueString())
+	if err != nil && !gdp.IsNotFound(err) {
+		addClientError(&resp.Diagnostics, "Error reading AWS Secrets Manager configuration", fmt.Sprintf("Could not read AWS Secrets Manager configuration: %s", err), err)
+		return
+	}
 
-		// Update the data with the values from the API
-		data.Name = types.StringValue(config.Name)
-		data.AuthType = types.StringValue(config.AuthType)
-		// We don't update sensitive fields from the API response
+	// If the resource doesn't exist, remove it from state
+	if config == nil {
+		resp.State.RemoveResource(ctx)
+		return
 	}
 
+	// Update the data with the values from the API
+	data.ID = types.StringValue(config.Name)
+	data.Name = types.StringValue(config.Name)
+	data.AuthType = types.StringValue(config.AuthType)
+	// We don't update sensitive fields from the API response
+
 	// Set state
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -208,6 +240,12 @@ func (r *AWSSecretsManagerResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating AWS Secrets Manager configuration")
 
 	config := gdp.NewAWSSecretsManagerConfig(
@@ -219,12 +257,15 @@ func (r *AWSSecretsManagerResource) Update(ctx context.Context, req resource.Upd
 		data.SecretKeyPassword.ValueString(),
 	)
 
-	if data.CaPath.IsNull() {
-		c := r.client.NewInsecureClient()
-		if err := c.UpdateAWSSecretsManager(ctx, data.AccessToken.ValueString(), config); err != nil {
-			resp.Diagnostics.AddError("Error updating AWS Secrets Manager configuration", fmt.Sprintf("Could not update AWS Secrets Manager configuration: %s", err))
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CaPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority", fmt.Sprintf("Could not load certificate authority: %s", err))
+		return
+	}
+
+	if err := c.UpdateAWSSecretsManager(ctx, data.AccessToken.ValueString(), config); err != nil {
+		addClientError(&resp.Diagnostics, "Error updating AWS Secrets Manager configuration", fmt.Sprintf("Could not update AWS Secrets Manager configuration: %s", err), err)
+		return
 	}
 
 	// Set state
@@ -241,13 +282,28 @@ func (r *AWSSecretsManagerResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting AWS Secrets Manager configuration")
 
-	if data.CaPath.IsNull() {
-		c := r.client.NewInsecureClient()
-		if err := c.DeleteAWSSecretsManager(ctx, data.AccessToken.ValueString(), data.Name.ValueString()); err != nil {
-			resp.Diagnostics.AddError("Error deleting AWS Secrets Manager configuration", fmt.Sprintf("Could not delete AWS Secrets Manager configuration: %s", err))
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CaPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority", fmt.Sprintf("Could not load certificate authority: %s", err))
+		return
+	}
+
+	// A configuration that is already gone needs no further action
+	if err := c.DeleteAWSSecretsManager(ctx, data.AccessToken.ValueString(), data.Name.ValueString()); err != nil && !gdp.IsNotFound(err) {
+		addClientError(&resp.Diagnostics, "Error deleting AWS Secrets Manager configuration", fmt.Sprintf("Could not delete AWS Secrets Manager configuration: %s", err), err)
+		return
 	}
 }
+
+// ImportState imports a configuration by name, which is also its ID
+func (r *AWSSecretsManagerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
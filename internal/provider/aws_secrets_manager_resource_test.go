@@ -0,0 +1,260 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	resourcetest "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+// nullTimeouts is an unset `timeouts` block
+func nullTimeouts() timeouts.Value {
+	return timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+		"create": types.StringType,
+		"read":   types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+	})}
+}
+
+// configuredResource returns r configured with api, and an empty state of its schema
+func configuredResource(t *testing.T, r resource.Resource, api gdp.API) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	configureResp := &resource.ConfigureResponse{}
+	r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: api}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected configure diagnostics: %v", configureResp.Diagnostics)
+	}
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	return tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+}
+
+// createResource runs the creation of r configured with api, planned as model
+func createResource(t *testing.T, r resource.Resource, api gdp.API, model any) *resource.CreateResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	emptyState := configuredResource(t, r, api)
+	plan := tfsdk.Plan{Schema: emptyState.Schema}
+	if diags := plan.Set(ctx, model); diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+
+	resp := &resource.CreateResponse{State: emptyState}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, resp)
+	return resp
+}
+
+// importResource runs the import of r configured with api with the import ID id
+func importResource(t *testing.T, r resource.Resource, api gdp.API, id string) *resource.ImportStateResponse {
+	t.Helper()
+
+	resp := &resource.ImportStateResponse{State: configuredResource(t, r, api)}
+	r.(resource.ResourceWithImportState).ImportState(context.Background(), resource.ImportStateRequest{ID: id}, resp)
+	return resp
+}
+
+func testAWSSecretsManagerModel(authType string) AWSSecretsManagerResourceModel {
+	return AWSSecretsManagerResourceModel{
+		AccessToken:       types.StringNull(),
+		Name:              types.StringValue("aws-prod"),
+		AuthType:          types.StringValue(authType),
+		AccessKeyID:       types.StringValue("AKIAEXAMPLE"),
+		SecretAccessKey:   types.StringValue("secret"),
+		SecretKeyUsername: types.StringValue("username"),
+		SecretKeyPassword: types.StringValue("password"),
+		ID:                types.StringUnknown(),
+		CaPath:            types.StringNull(),
+		Timeouts:          nullTimeouts(),
+	}
+}
+
+func TestAWSSecretsManagerResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := gdptest.NewFake()
+	r := NewAWSSecretsManagerResource()
+	emptyState := configuredResource(t, r, fake)
+
+	plan := tfsdk.Plan{Schema: emptyState.Schema}
+	model := testAWSSecretsManagerModel("Security-Credentials")
+	if diags := plan.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+
+	createResp := &resource.CreateResponse{State: emptyState}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected create diagnostics: %v", createResp.Diagnostics)
+	}
+	if config, ok := fake.AWSSecretsManagers["aws-prod"]; !ok || config.AccessKeyID != "AKIAEXAMPLE" {
+		t.Fatalf("Expected the configuration to be created, got %+v", fake.AWSSecretsManagers)
+	}
+	var created AWSSecretsManagerResourceModel
+	createResp.State.Get(ctx, &created)
+	if created.ID.ValueString() != "aws-prod" {
+		t.Errorf("Expected ID aws-prod, got %s", created.ID)
+	}
+
+	// Changes made outside of Terraform are read back
+	fake.AWSSecretsManagers["aws-prod"] = gdp.AWSSecretsManagerConfig{Name: "aws-prod", AuthType: "IAM-Role"}
+	readResp := &resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, readResp)
+	var read AWSSecretsManagerResourceModel
+	readResp.State.Get(ctx, &read)
+	if read.AuthType.ValueString() != "IAM-Role" {
+		t.Errorf("Expected the auth type to be refreshed, got %s", read.AuthType)
+	}
+
+	model = testAWSSecretsManagerModel("Security-Credentials")
+	model.ID = types.StringValue("aws-prod")
+	plan.Set(ctx, &model)
+	updateResp := &resource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, resource.UpdateRequest{Plan: plan, State: readResp.State}, updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected update diagnostics: %v", updateResp.Diagnostics)
+	}
+	if fake.AWSSecretsManagers["aws-prod"].AuthType != "Security-Credentials" {
+		t.Errorf("Expected the configuration to be updated, got %+v", fake.AWSSecretsManagers["aws-prod"])
+	}
+
+	deleteResp := &resource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected delete diagnostics: %v", deleteResp.Diagnostics)
+	}
+	if len(fake.AWSSecretsManagers) != 0 {
+		t.Errorf("Expected the configuration to be deleted, got %+v", fake.AWSSecretsManagers)
+	}
+
+	// A configuration deleted outside of Terraform is removed from the state
+	readResp = &resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{State: updateResp.State}, readResp)
+	if !readResp.State.Raw.IsNull() {
+		t.Error("Expected the resource to be removed from the state")
+	}
+}
+
+func TestAWSSecretsManagerResourceErrors(t *testing.T) {
+	testCases := []struct {
+		name            string
+		err             error
+		expectedSummary string
+	}{
+		{name: "Appliance failure", err: &gdp.APIError{StatusCode: 500, Endpoint: "POST /restAPI/aws_secrets_manager"}, expectedSummary: "Error creating AWS Secrets Manager configuration"},
+		{name: "Canceled", err: context.Canceled, expectedSummary: "Operation canceled"},
+		{name: "Timed out", err: context.DeadlineExceeded, expectedSummary: "Operation timed out"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := gdptest.NewFake()
+			fake.SetError("CreateAWSSecretsManager", tc.err)
+			r := NewAWSSecretsManagerResource()
+			emptyState := configuredResource(t, r, fake)
+
+			plan := tfsdk.Plan{Schema: emptyState.Schema}
+			model := testAWSSecretsManagerModel("Security-Credentials")
+			plan.Set(ctx, &model)
+
+			resp := &resource.CreateResponse{State: emptyState}
+			r.Create(ctx, resource.CreateRequest{Plan: plan}, resp)
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("Expected an error diagnostic")
+			}
+			if summary := resp.Diagnostics.Errors()[0].Summary(); summary != tc.expectedSummary {
+				t.Errorf("Expected summary %q, got %q", tc.expectedSummary, summary)
+			}
+		})
+	}
+}
+
+func testAccAWSSecretsManagerConfig(appliance *testAccAppliance, authType string) string {
+	return appliance.config(`
+resource "guardium-data-protection_aws_secrets_manager" "test" {
+  name                = "aws-prod"
+  auth_type           = %q
+  access_key_id       = "AKIAEXAMPLE"
+  secret_access_key   = "secret"
+  secret_key_username = "username"
+  secret_key_password = "password"
+  ca_path             = %q
+}
+`, authType, appliance.CAFile)
+}
+
+func TestAccAWSSecretsManagerResource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resourcetest.Test(t, resourcetest.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if config := appliance.AWSSecretsManager("aws-prod"); config != nil {
+				return fmt.Errorf("expected the configuration to be deleted, got %+v", config)
+			}
+			return nil
+		},
+		Steps: []resourcetest.TestStep{
+			{
+				Config: testAccAWSSecretsManagerConfig(appliance, "Security-Credentials"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("guardium-data-protection_aws_secrets_manager.test", tfjsonpath.New("id"), knownvalue.StringExact("aws-prod")),
+				},
+				Check: func(*terraform.State) error {
+					// Setting ca_path must not skip the call to the appliance
+					if config := appliance.AWSSecretsManager("aws-prod"); config == nil || config.AuthType != "Security-Credentials" {
+						return fmt.Errorf("expected the configuration to be created, got %+v", config)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccAWSSecretsManagerConfig(appliance, "IAM-Role"),
+				ConfigPlanChecks: resourcetest.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("guardium-data-protection_aws_secrets_manager.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(*terraform.State) error {
+					if config := appliance.AWSSecretsManager("aws-prod"); config == nil || config.AuthType != "IAM-Role" {
+						return fmt.Errorf("expected the configuration to be updated, got %+v", config)
+					}
+					return nil
+				},
+			},
+			{
+				// The secrets are not read back from the appliance
+				ResourceName:            "guardium-data-protection_aws_secrets_manager.test",
+				ImportState:             true,
+				ImportStateId:           "aws-prod",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"access_key_id", "secret_access_key", "secret_key_username", "secret_key_password", "ca_path"},
+			},
+		},
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -32,20 +33,22 @@ func NewConfigureVADatasourceResource() resource.Resource {
 
 // configureVADatasourceResource is the resource implementation.
 type configureVADatasourceResource struct {
-	client *gdp.Client
+	client gdp.API
 }
 
 // configureVADatasourceResourceModel maps the resource schema data.
 type configureVADatasourceResourceModel struct {
-	ID                 types.String `tfsdk:"id"`
-	DatasourceName     types.String `tfsdk:"datasource_name"`
-	AssessmentSchedule types.String `tfsdk:"assessment_schedule"`
-	AssessmentDay      types.String `tfsdk:"assessment_day"`
-	AssessmentTime     types.String `tfsdk:"assessment_time"`
-	Enabled            types.Bool   `tfsdk:"enabled"`
-	AccessToken        types.String `tfsdk:"access_token"`
-	LastConfiguredTime types.String `tfsdk:"last_configured_time"`
-	CAPath             types.String `tfsdk:"ca_path"`
+	ID                 types.String   `tfsdk:"id"`
+	DatasourceName     types.String   `tfsdk:"datasource_name"`
+	AssessmentSchedule types.String   `tfsdk:"assessment_schedule"`
+	AssessmentDay      types.String   `tfsdk:"assessment_day"`
+	AssessmentTime     types.String   `tfsdk:"assessment_time"`
+	Enabled            types.Bool     `tfsdk:"enabled"`
+	AccessToken        types.String   `tfsdk:"access_token"`
+	LastConfiguredTime types.String   `tfsdk:"last_configured_time"`
+	CAPath             types.String   `tfsdk:"ca_path"`
+	TargetUnit         types.String   `tfsdk:"target_unit"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *configureVADatasourceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -80,12 +83,12 @@ func (r *configureVADatasourceResource) Schema(ctx context.Context, req resource
 				Computed:            true,
 			},
 			"access_token": schema.StringAttribute{
-				MarkdownDescription: "Access token for authentication",
-				Required:            true,
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
 				Sensitive:           true,
 			},
 			"ca_path": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection certificate authority",
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
 				Optional:            true,
 			},
 			"last_configured_time": schema.StringAttribute{
@@ -99,6 +102,10 @@ func (r *configureVADatasourceResource) Schema(ctx context.Context, req resource
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"target_unit": targetUnitAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -109,11 +116,11 @@ func (r *configureVADatasourceResource) Configure(ctx context.Context, req resou
 		return
 	}
 
-	client, ok := req.ProviderData.(*gdp.Client)
+	client, ok := req.ProviderData.(gdp.API)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *gdp.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected gdp.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -130,6 +137,13 @@ func (r *configureVADatasourceResource) Create(ctx context.Context, req resource
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
 	// Create HTTP client
 
 	// Prepare the payload
@@ -148,15 +162,24 @@ func (r *configureVADatasourceResource) Create(ctx context.Context, req resource
 		return
 	}
 
-	if data.CAPath.IsNull() {
-		err = r.client.NewInsecureClient().ConfigureVADataSource(ctx, data.AccessToken.ValueString(), payload)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to register va",
-				fmt.Sprintf("Failed to register va: %s.", err.Error()),
-			)
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	err = c.ConfigureVADataSource(ctx, data.AccessToken.ValueString(), payload)
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Failed to register va",
+			fmt.Sprintf("Failed to register va: %s.", err.Error()),
+			err,
+		)
+		return
 	}
 
 	// Set computed values
@@ -196,6 +219,13 @@ func (r *configureVADatasourceResource) Update(ctx context.Context, req resource
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
 	// Prepare the payload
 	payload, err := gdp.NewConfigureDatasourcePayloadBuilder().
 		DatasourceName(data.DatasourceName.ValueString()).
@@ -212,15 +242,24 @@ func (r *configureVADatasourceResource) Update(ctx context.Context, req resource
 		return
 	}
 
-	if data.CAPath.IsNull() {
-		err = r.client.NewInsecureClient().ConfigureVADataSource(ctx, data.AccessToken.ValueString(), payload)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to register va",
-				fmt.Sprintf("Failed to register va: %s.", err.Error()),
-			)
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	err = c.ConfigureVADataSource(ctx, data.AccessToken.ValueString(), payload)
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Failed to register va",
+			fmt.Sprintf("Failed to register va: %s.", err.Error()),
+			err,
+		)
+		return
 	}
 
 	// Set computed values
@@ -241,6 +280,34 @@ func (r *configureVADatasourceResource) Delete(ctx context.Context, req resource
 	}
 }
 
+// configureVADatasourceImport is the import ID of the resource. The appliance does not report the assessment
+// configuration, so it is imported with the settings it was configured with.
+type configureVADatasourceImport struct {
+	DatasourceName     string `json:"datasource_name"`
+	AssessmentSchedule string `json:"assessment_schedule"`
+	AssessmentDay      string `json:"assessment_day"`
+	AssessmentTime     string `json:"assessment_time"`
+}
+
 func (r *configureVADatasourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	var settings configureVADatasourceImport
+	decodeImportID(req.ID, &settings, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if settings.DatasourceName == "" || settings.AssessmentSchedule == "" || settings.AssessmentDay == "" || settings.AssessmentTime == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"The import ID must set `datasource_name`, `assessment_schedule`, `assessment_day` and `assessment_time`.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("va-config-%s", settings.DatasourceName))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("datasource_name"), settings.DatasourceName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("assessment_schedule"), settings.AssessmentSchedule)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("assessment_day"), settings.AssessmentDay)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("assessment_time"), settings.AssessmentTime)...)
+	// Assessments are always configured enabled
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("enabled"), true)...)
 }
@@ -0,0 +1,162 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+func testConfigureVADatasourceModel() configureVADatasourceResourceModel {
+	return configureVADatasourceResourceModel{
+		ID:                 types.StringUnknown(),
+		DatasourceName:     types.StringValue("db2-prod"),
+		AssessmentSchedule: types.StringValue("weekly"),
+		AssessmentDay:      types.StringValue("Monday"),
+		AssessmentTime:     types.StringValue("23:00"),
+		Enabled:            types.BoolValue(true),
+		AccessToken:        types.StringNull(),
+		LastConfiguredTime: types.StringUnknown(),
+		CAPath:             types.StringValue("/etc/guardium/ca.pem"),
+		TargetUnit:         types.StringNull(),
+		Timeouts:           nullTimeouts(),
+	}
+}
+
+func TestConfigureVADatasourceResourceCreate(t *testing.T) {
+	fake := gdptest.NewFake()
+	model := testConfigureVADatasourceModel()
+	resp := createResource(t, NewConfigureVADatasourceResource(), fake, &model)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected create diagnostics: %v", resp.Diagnostics)
+	}
+
+	var config gdp.ConfigureDatasourcePayload
+	if len(fake.VAConfigs) != 1 || json.Unmarshal(fake.VAConfigs[0], &config) != nil {
+		t.Fatalf("Expected the configuration to be sent once, got %s", fake.VAConfigs)
+	}
+	if config.DatasourceName != "db2-prod" || config.Schedule != (gdp.VASchedule{Frequency: "weekly", Day: "Monday", Time: "23:00"}) || !config.Enabled {
+		t.Errorf("Unexpected configuration %+v", config)
+	}
+	if len(fake.CAPaths) != 1 || fake.CAPaths[0] != "/etc/guardium/ca.pem" {
+		t.Errorf("Expected the certificate authority of the resource to be used, got %v", fake.CAPaths)
+	}
+
+	var created configureVADatasourceResourceModel
+	resp.State.Get(context.Background(), &created)
+	if created.ID.ValueString() != "va-config-db2-prod" || created.LastConfiguredTime.ValueString() == "" {
+		t.Errorf("Expected ID va-config-db2-prod and the configuration time, got %s and %s", created.ID, created.LastConfiguredTime)
+	}
+
+	fake.SetError("ConfigureVADataSource", errors.New("appliance unavailable"))
+	resp = createResource(t, NewConfigureVADatasourceResource(), fake, &model)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Failed to register va" {
+		t.Errorf("Expected the failure to be reported, got %v", resp.Diagnostics)
+	}
+}
+
+func TestConfigureVADatasourceResourceImportState(t *testing.T) {
+	testCases := []struct {
+		name          string
+		id            string
+		expectedError string
+	}{
+		{name: "Settings", id: `{"datasource_name":"db2-prod","assessment_schedule":"weekly","assessment_day":"Monday","assessment_time":"23:00"}`},
+		{name: "Missing setting", id: `{"datasource_name":"db2-prod","assessment_schedule":"weekly"}`, expectedError: "must set"},
+		{name: "Unknown setting", id: `{"datasource_name":"db2-prod","schedule":"weekly"}`, expectedError: "unknown field"},
+		{name: "Resource ID", id: "va-config-db2-prod", expectedError: "as a JSON object"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := importResource(t, NewConfigureVADatasourceResource(), gdptest.NewFake(), tc.id)
+			if tc.expectedError != "" {
+				if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), tc.expectedError) {
+					t.Errorf("Expected an error containing %q, got %v", tc.expectedError, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected import diagnostics: %v", resp.Diagnostics)
+			}
+
+			var imported configureVADatasourceResourceModel
+			resp.State.Get(context.Background(), &imported)
+			if imported.ID.ValueString() != "va-config-db2-prod" || imported.AssessmentDay.ValueString() != "Monday" || !imported.Enabled.ValueBool() {
+				t.Errorf("Unexpected imported state %+v", imported)
+			}
+		})
+	}
+}
+
+func testAccConfigureVADatasourceConfig(appliance *testAccAppliance, day string) string {
+	return appliance.config(`
+resource "guardium-data-protection_configure_va_datasource" "test" {
+  datasource_name     = "db2-prod"
+  assessment_schedule = "weekly"
+  assessment_day      = %q
+  assessment_time     = "23:00"
+  enabled             = true
+  ca_path             = %q
+}
+`, day, appliance.CAFile)
+}
+
+// testAccCheckVASchedule checks the day the mock appliance schedules the db2-prod assessment on
+func testAccCheckVASchedule(appliance *testAccAppliance, day string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		var config gdp.ConfigureDatasourcePayload
+		if err := json.Unmarshal(appliance.VAConfig("db2-prod"), &config); err != nil || config.Schedule.Day != day {
+			return fmt.Errorf("expected the assessment to run on %s, got %s", day, appliance.VAConfig("db2-prod"))
+		}
+		return nil
+	}
+}
+
+func TestAccConfigureVADatasourceResource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfigureVADatasourceConfig(appliance, "Monday"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("guardium-data-protection_configure_va_datasource.test", "id", "va-config-db2-prod"),
+					testAccCheckVASchedule(appliance, "Monday"),
+				),
+			},
+			{
+				Config: testAccConfigureVADatasourceConfig(appliance, "Friday"),
+				Check:  testAccCheckVASchedule(appliance, "Friday"),
+			},
+			{
+				ResourceName:      "guardium-data-protection_configure_va_datasource.test",
+				ImportState:       true,
+				ImportStateId:     `{"datasource_name":"db2-prod","assessment_schedule":"weekly","assessment_day":"Friday","assessment_time":"23:00"}`,
+				ImportStateVerify: true,
+				// The certificate authority only applies to requests and the time is that of the last apply
+				ImportStateVerifyIgnore: []string{"ca_path", "last_configured_time"},
+			},
+			{
+				ResourceName:  "guardium-data-protection_configure_va_datasource.test",
+				ImportState:   true,
+				ImportStateId: "va-config-db2-prod",
+				ExpectError:   regexp.MustCompile(`Invalid import ID`),
+			},
+		},
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -33,7 +34,7 @@ func NewConfigureVANotificationsResource() resource.Resource {
 
 // configureVANotificationsResource is the resource implementation.
 type configureVANotificationsResource struct {
-	client *gdp.Client
+	client gdp.API
 }
 
 // configureVANotificationsResourceModel maps the resource schema data.
@@ -47,6 +48,8 @@ type configureVANotificationsResourceModel struct {
 	AccessToken          types.String   `tfsdk:"access_token"`
 	LastConfiguredTime   types.String   `tfsdk:"last_configured_time"`
 	CAPath               types.String   `tfsdk:"ca_path"`
+	TargetUnit           types.String   `tfsdk:"target_unit"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *configureVANotificationsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -83,12 +86,12 @@ func (r *configureVANotificationsResource) Schema(ctx context.Context, req resou
 				Default:             booldefault.StaticBool(true),
 			},
 			"access_token": schema.StringAttribute{
-				MarkdownDescription: "Access token for authentication",
-				Required:            true,
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
 				Sensitive:           true,
 			},
 			"ca_path": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection certificate authority",
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
 				Optional:            true,
 			},
 			"last_configured_time": schema.StringAttribute{
@@ -102,6 +105,10 @@ func (r *configureVANotificationsResource) Schema(ctx context.Context, req resou
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"target_unit": targetUnitAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -112,11 +119,11 @@ func (r *configureVANotificationsResource) Configure(ctx context.Context, req re
 		return
 	}
 
-	client, ok := req.ProviderData.(*gdp.Client)
+	client, ok := req.ProviderData.(gdp.API)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *gdp.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected gdp.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -133,6 +140,13 @@ func (r *configureVANotificationsResource) Create(ctx context.Context, req resou
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
 	// Convert notification emails from types.String to string
 	notificationEmails := make([]string, 0, len(data.NotificationEmails))
 	for _, email := range data.NotificationEmails {
@@ -156,15 +170,24 @@ func (r *configureVANotificationsResource) Create(ctx context.Context, req resou
 		return
 	}
 
-	if data.CAPath.IsNull() {
-		err = r.client.NewInsecureClient().ConfigureVANotifications(ctx, data.AccessToken.ValueString(), payload)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to register va",
-				fmt.Sprintf("Failed to register va: %s.", err.Error()),
-			)
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	err = c.ConfigureVANotifications(ctx, data.AccessToken.ValueString(), payload)
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Failed to register va",
+			fmt.Sprintf("Failed to register va: %s.", err.Error()),
+			err,
+		)
+		return
 	}
 
 	// Set computed values
@@ -204,6 +227,13 @@ func (r *configureVANotificationsResource) Update(ctx context.Context, req resou
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
 	// Convert notification emails from types.String to string
 	notificationEmails := make([]string, 0, len(data.NotificationEmails))
 	for _, email := range data.NotificationEmails {
@@ -227,15 +257,24 @@ func (r *configureVANotificationsResource) Update(ctx context.Context, req resou
 		return
 	}
 
-	if data.CAPath.IsNull() {
-		err = r.client.NewInsecureClient().ConfigureVANotifications(ctx, data.AccessToken.ValueString(), payload)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to register va",
-				fmt.Sprintf("Failed to register va: %s.", err.Error()),
-			)
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	err = c.ConfigureVANotifications(ctx, data.AccessToken.ValueString(), payload)
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Failed to register va",
+			fmt.Sprintf("Failed to register va: %s.", err.Error()),
+			err,
+		)
+		return
 	}
 
 	// Set computed values
@@ -262,6 +301,34 @@ func (r *configureVANotificationsResource) Delete(ctx context.Context, req resou
 	// No action needed on delete - the resource will be removed from state
 }
 
+// configureVANotificationsImport is the import ID of the resource. The appliance does not report the
+// notification configuration, so it is imported with the settings it was configured with.
+type configureVANotificationsImport struct {
+	DatasourceName       string   `json:"datasource_name"`
+	NotificationType     string   `json:"notification_type"`
+	NotificationEmails   []string `json:"notification_emails"`
+	NotificationSeverity string   `json:"notification_severity"`
+}
+
 func (r *configureVANotificationsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	var settings configureVANotificationsImport
+	decodeImportID(req.ID, &settings, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if settings.DatasourceName == "" || settings.NotificationType == "" || len(settings.NotificationEmails) == 0 || settings.NotificationSeverity == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"The import ID must set `datasource_name`, `notification_type`, `notification_emails` and `notification_severity`.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("va-notifications-%s", settings.DatasourceName))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("datasource_name"), settings.DatasourceName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("notification_type"), settings.NotificationType)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("notification_emails"), settings.NotificationEmails)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("notification_severity"), settings.NotificationSeverity)...)
+	// Notifications are always configured enabled
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("enabled"), true)...)
 }
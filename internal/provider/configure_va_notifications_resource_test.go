@@ -0,0 +1,138 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+func testConfigureVANotificationsModel() configureVANotificationsResourceModel {
+	return configureVANotificationsResourceModel{
+		ID:                   types.StringUnknown(),
+		DatasourceName:       types.StringValue("db2-prod"),
+		NotificationType:     types.StringValue("email"),
+		NotificationEmails:   []types.String{types.StringValue("dba@example.com"), types.StringValue("security@example.com")},
+		NotificationSeverity: types.StringValue("high"),
+		Enabled:              types.BoolValue(true),
+		AccessToken:          types.StringValue("explicit-token"),
+		LastConfiguredTime:   types.StringUnknown(),
+		CAPath:               types.StringNull(),
+		TargetUnit:           types.StringNull(),
+		Timeouts:             nullTimeouts(),
+	}
+}
+
+func TestConfigureVANotificationsResourceCreate(t *testing.T) {
+	// Without provider credentials the access token of the resource is used
+	fake := &gdptest.Fake{}
+	model := testConfigureVANotificationsModel()
+	resp := createResource(t, NewConfigureVANotificationsResource(), fake, &model)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected create diagnostics: %v", resp.Diagnostics)
+	}
+
+	var config gdp.ConfigureNotificationsPayload
+	if len(fake.VANotifications) != 1 || json.Unmarshal(fake.VANotifications[0], &config) != nil {
+		t.Fatalf("Expected the notifications to be configured once, got %s", fake.VANotifications)
+	}
+	if config.DatasourceName != "db2-prod" || strings.Join(config.Recipients, ",") != "dba@example.com,security@example.com" || config.Severity != "high" {
+		t.Errorf("Unexpected configuration %+v", config)
+	}
+
+	var created configureVANotificationsResourceModel
+	resp.State.Get(context.Background(), &created)
+	if created.ID.ValueString() != "va-notifications-db2-prod" {
+		t.Errorf("Expected ID va-notifications-db2-prod, got %s", created.ID)
+	}
+
+	fake.SetError("ConfigureVANotifications", context.DeadlineExceeded)
+	resp = createResource(t, NewConfigureVANotificationsResource(), fake, &model)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Operation timed out" {
+		t.Errorf("Expected the timeout to be reported, got %v", resp.Diagnostics)
+	}
+}
+
+func TestConfigureVANotificationsResourceImportState(t *testing.T) {
+	resp := importResource(t, NewConfigureVANotificationsResource(), gdptest.NewFake(),
+		`{"datasource_name":"db2-prod","notification_type":"email","notification_emails":["dba@example.com"],"notification_severity":"high"}`)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected import diagnostics: %v", resp.Diagnostics)
+	}
+	var imported configureVANotificationsResourceModel
+	resp.State.Get(context.Background(), &imported)
+	if imported.ID.ValueString() != "va-notifications-db2-prod" || len(imported.NotificationEmails) != 1 || imported.NotificationEmails[0].ValueString() != "dba@example.com" {
+		t.Errorf("Unexpected imported state %+v", imported)
+	}
+
+	resp = importResource(t, NewConfigureVANotificationsResource(), gdptest.NewFake(), `{"datasource_name":"db2-prod","notification_emails":[]}`)
+	if !resp.Diagnostics.HasError() {
+		t.Error("Expected an import ID without recipients to be rejected")
+	}
+}
+
+func testAccConfigureVANotificationsConfig(appliance *testAccAppliance, emails ...string) string {
+	return appliance.config(`
+resource "guardium-data-protection_configure_va_notifications" "test" {
+  datasource_name       = "db2-prod"
+  notification_type     = "email"
+  notification_emails   = ["%s"]
+  notification_severity = "high"
+  ca_path               = %q
+}
+`, strings.Join(emails, `", "`), appliance.CAFile)
+}
+
+// testAccCheckVARecipients checks the recipients of the db2-prod notifications on the mock appliance
+func testAccCheckVARecipients(appliance *testAccAppliance, emails ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		var config struct {
+			Recipients []string `json:"recipients"`
+		}
+		if err := json.Unmarshal(appliance.VANotifications("db2-prod"), &config); err != nil || strings.Join(config.Recipients, ",") != strings.Join(emails, ",") {
+			return fmt.Errorf("expected notifications to be sent to %v, got %s", emails, appliance.VANotifications("db2-prod"))
+		}
+		return nil
+	}
+}
+
+func TestAccConfigureVANotificationsResource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfigureVANotificationsConfig(appliance, "dba@example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("guardium-data-protection_configure_va_notifications.test", "id", "va-notifications-db2-prod"),
+					resource.TestCheckResourceAttr("guardium-data-protection_configure_va_notifications.test", "enabled", "true"),
+					testAccCheckVARecipients(appliance, "dba@example.com"),
+				),
+			},
+			{
+				Config: testAccConfigureVANotificationsConfig(appliance, "dba@example.com", "security@example.com"),
+				Check:  testAccCheckVARecipients(appliance, "dba@example.com", "security@example.com"),
+			},
+			{
+				ResourceName:      "guardium-data-protection_configure_va_notifications.test",
+				ImportState:       true,
+				ImportStateId:     `{"datasource_name":"db2-prod","notification_type":"email","notification_emails":["dba@example.com","security@example.com"],"notification_severity":"high"}`,
+				ImportStateVerify: true,
+				// The certificate authority only applies to requests and the time is that of the last apply
+				ImportStateVerifyIgnore: []string{"ca_path", "last_configured_time"},
+			},
+		},
+	})
+}
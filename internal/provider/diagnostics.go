@@ -0,0 +1,35 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// addClientError adds the error returned by a gdp operation to diags. Operations interrupted by Terraform or
+// stopped by a timeout are reported as such instead, so that they are not mistaken for appliance failures.
+func addClientError(diags *diag.Diagnostics, summary, detail string, err error) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		diags.AddError(
+			"Operation timed out",
+			fmt.Sprintf("%s: %s. The appliance may still complete the operation. Increase the resource `timeouts` "+
+				"or the provider `request_timeout` when the appliance needs longer.", summary, strings.TrimSuffix(detail, ".")),
+		)
+	case gdp.IsCanceled(err):
+		diags.AddError(
+			"Operation canceled",
+			fmt.Sprintf("%s: %s. The operation was interrupted and the appliance may have partially applied it.", summary, strings.TrimSuffix(detail, ".")),
+		)
+	default:
+		diags.AddError(summary, detail)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// Environment variables read when the matching provider attribute is not configured
+const (
+	envHost         = "GUARDIUM_HOST"
+	envPort         = "GUARDIUM_PORT"
+	envClientID     = "GUARDIUM_CLIENT_ID"
+	envClientSecret = "GUARDIUM_CLIENT_SECRET"
+	envUsername     = "GUARDIUM_USERNAME"
+	envPassword     = "GUARDIUM_PASSWORD"
+	envCAFile       = "GUARDIUM_CA_FILE"
+	envCAPEM        = "GUARDIUM_CA_PEM"
+)
+
+// Environment variables recording the requests sent to the appliance to a fixture file, or replaying them from
+// one, for tests and offline development. They have no provider attribute.
+const (
+	envFixturesMode = "GUARDIUM_FIXTURES_MODE"
+	envFixturesFile = "GUARDIUM_FIXTURES_FILE"
+)
+
+// sourceConfig identifies values set in the provider block
+const sourceConfig = "the provider configuration"
+
+// settingSources records where each provider setting was taken from, so that diagnostics can tell users
+// whether to fix their configuration or their environment
+type settingSources map[string]string
+
+// resolve leaves value as is when it is configured, and otherwise sets it from envVar when that is set and
+// not empty. Unknown values are left for Terraform to resolve, they never fall back to the environment.
+func (s settingSources) resolve(value *types.String, attribute, envVar string) {
+	if !value.IsNull() {
+		s[attribute] = sourceConfig
+		return
+	}
+
+	if env := os.Getenv(envVar); env != "" {
+		*value = types.StringValue(env)
+		s[attribute] = fmt.Sprintf("the %s environment variable", envVar)
+	}
+}
+
+// describe lists the source of each given attribute that has a value, e.g.
+// "host from the GUARDIUM_HOST environment variable, port from the provider configuration"
+func (s settingSources) describe(attributes ...string) string {
+	var parts []string
+	for _, attribute := range attributes {
+		if source, ok := s[attribute]; ok {
+			parts = append(parts, fmt.Sprintf("%s from %s", attribute, source))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// fields returns every recorded source, for logging
+func (s settingSources) fields() map[string]any {
+	attributes := make([]string, 0, len(s))
+	for attribute := range s {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	fields := make(map[string]any, len(s))
+	for _, attribute := range attributes {
+		fields[attribute] = s[attribute]
+	}
+	return fields
+}
+
+// applyEnvironment fills host, port, the credentials and the certificate authority from the GUARDIUM_*
+// environment variables where the configuration leaves them unset, and returns where each value came from
+func (m *guardiumDataProtectionModel) applyEnvironment(diags *diag.Diagnostics) settingSources {
+	sources := settingSources{}
+
+	sources.resolve(&m.Host, "host", envHost)
+	sources.resolve(&m.Port, "port", envPort)
+	sources.resolve(&m.ClientID, "client_id", envClientID)
+	sources.resolve(&m.Username, "username", envUsername)
+
+	// Secrets read from a file are not taken from the environment
+	for _, secret := range []struct {
+		value, file    *types.String
+		attribute, env string
+	}{
+		{&m.ClientSecret, &m.ClientSecretFile, "client_secret", envClientSecret},
+		{&m.Password, &m.PasswordFile, "password", envPassword},
+	} {
+		if secret.file.IsNull() {
+			sources.resolve(secret.value, secret.attribute, secret.env)
+			continue
+		}
+		sources[secret.attribute+"_file"] = sourceConfig
+	}
+	if !m.CredentialProcess.IsNull() {
+		sources["credential_process"] = sourceConfig
+	}
+
+	// The certificate authority is taken from the environment only when the configuration sets neither form
+	if m.TLS != nil && (!m.TLS.CAFile.IsNull() || !m.TLS.CAPEM.IsNull()) {
+		if !m.TLS.CAFile.IsNull() {
+			sources["tls.ca_file"] = sourceConfig
+		}
+		if !m.TLS.CAPEM.IsNull() {
+			sources["tls.ca_pem"] = sourceConfig
+		}
+		return sources
+	}
+
+	caFile, caPEM := os.Getenv(envCAFile), os.Getenv(envCAPEM)
+	if caFile != "" && caPEM != "" {
+		diags.AddError(
+			"Conflicting certificate authority environment variables",
+			fmt.Sprintf("Only one of the %s and %s environment variables can be set.", envCAFile, envCAPEM),
+		)
+		return sources
+	}
+	if caFile != "" || caPEM != "" {
+		if m.TLS == nil {
+			m.TLS = &tlsModel{}
+		}
+		sources.resolve(&m.TLS.CAFile, "tls.ca_file", envCAFile)
+		sources.resolve(&m.TLS.CAPEM, "tls.ca_pem", envCAPEM)
+	}
+
+	return sources
+}
+
+// fixtureConfigFromEnvironment returns the fixture settings of the GUARDIUM_FIXTURES_* environment variables
+func fixtureConfigFromEnvironment() gdp.FixtureConfig {
+	return gdp.FixtureConfig{
+		Mode: gdp.FixtureMode(os.Getenv(envFixturesMode)),
+		Path: os.Getenv(envFixturesFile),
+	}
+}
+
+// requireSetting reports a setting that is unknown, or missing from both the configuration and the environment
+func requireSetting(value types.String, attribute, envVar string, diags *diag.Diagnostics) {
+	switch {
+	case value.IsUnknown():
+		diags.AddAttributeError(
+			path.Root(attribute),
+			fmt.Sprintf("Unknown Guardium Data Protection %s", attribute),
+			fmt.Sprintf("The provider cannot create the Guardium Data Protection client as there is an unknown configuration value for `%s`. "+
+				"Either apply the source of the value first, set the value statically in the configuration, or use the %s environment variable.", attribute, envVar),
+		)
+	case value.ValueString() == "":
+		diags.AddAttributeError(
+			path.Root(attribute),
+			fmt.Sprintf("Missing Guardium Data Protection %s", attribute),
+			fmt.Sprintf("Set `%s` in the provider configuration or the %s environment variable.", attribute, envVar),
+		)
+	}
+}
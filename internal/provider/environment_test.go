@@ -0,0 +1,147 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestApplyEnvironment(t *testing.T) {
+	testCases := []struct {
+		name             string
+		model            guardiumDataProtectionModel
+		env              map[string]string
+		expectedHost     types.String
+		expectedPort     types.String
+		expectedPassword types.String
+		expectedCAFile   types.String
+		expectedSources  string
+		expectedError    string
+	}{
+		{
+			name:            "Configuration over environment",
+			model:           guardiumDataProtectionModel{Host: types.StringValue("config.example.com")},
+			env:             map[string]string{envHost: "env.example.com", envPort: "8443"},
+			expectedHost:    types.StringValue("config.example.com"),
+			expectedPort:    types.StringValue("8443"),
+			expectedSources: "host from the provider configuration, port from the GUARDIUM_PORT environment variable",
+		},
+		{
+			name:            "Empty environment variable",
+			env:             map[string]string{envHost: "env.example.com", envPort: ""},
+			expectedHost:    types.StringValue("env.example.com"),
+			expectedSources: "host from the GUARDIUM_HOST environment variable",
+		},
+		{
+			name:         "Unknown value",
+			model:        guardiumDataProtectionModel{Host: types.StringUnknown()},
+			env:          map[string]string{envHost: "env.example.com"},
+			expectedHost: types.StringUnknown(),
+			// Unknown values are still from the configuration, Terraform resolves them later
+			expectedSources: "host from the provider configuration",
+		},
+		{
+			name:            "Password file over environment",
+			model:           guardiumDataProtectionModel{PasswordFile: types.StringValue("/run/secrets/guardium")},
+			env:             map[string]string{envPassword: "secret"},
+			expectedSources: "password_file from the provider configuration",
+		},
+		{
+			name:             "Password from environment",
+			env:              map[string]string{envPassword: "secret"},
+			expectedPassword: types.StringValue("secret"),
+			expectedSources:  "password from the GUARDIUM_PASSWORD environment variable",
+		},
+		{
+			name:            "Certificate authority from environment",
+			env:             map[string]string{envCAFile: "/etc/guardium/ca.pem"},
+			expectedCAFile:  types.StringValue("/etc/guardium/ca.pem"),
+			expectedSources: "tls.ca_file from the GUARDIUM_CA_FILE environment variable",
+		},
+		{
+			name:            "Certificate authority configured",
+			model:           guardiumDataProtectionModel{TLS: &tlsModel{CAPEM: types.StringValue("-----BEGIN CERTIFICATE-----")}},
+			env:             map[string]string{envCAFile: "/etc/guardium/ca.pem"},
+			expectedSources: "tls.ca_pem from the provider configuration",
+		},
+		{
+			name:          "Conflicting certificate authority environment variables",
+			env:           map[string]string{envCAFile: "/etc/guardium/ca.pem", envCAPEM: "-----BEGIN CERTIFICATE-----"},
+			expectedError: "Conflicting certificate authority environment variables",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, env := range []string{envHost, envPort, envClientID, envClientSecret, envUsername, envPassword, envCAFile, envCAPEM} {
+				t.Setenv(env, tc.env[env])
+			}
+
+			var diags diag.Diagnostics
+			sources := tc.model.applyEnvironment(&diags)
+			if tc.expectedError != "" {
+				if !diags.HasError() || diags.Errors()[0].Summary() != tc.expectedError {
+					t.Errorf("Expected error %q, got %v", tc.expectedError, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("Expected no error but got: %v", diags)
+			}
+
+			if !tc.model.Host.Equal(tc.expectedHost) || !tc.model.Port.Equal(tc.expectedPort) || !tc.model.Password.Equal(tc.expectedPassword) {
+				t.Errorf("Expected host %s, port %s and password %s, got %s, %s and %s",
+					tc.expectedHost, tc.expectedPort, tc.expectedPassword, tc.model.Host, tc.model.Port, tc.model.Password)
+			}
+			gotCAFile := types.StringNull()
+			if tc.model.TLS != nil {
+				gotCAFile = tc.model.TLS.CAFile
+			}
+			if !gotCAFile.Equal(tc.expectedCAFile) {
+				t.Errorf("Expected tls.ca_file %s, got %s", tc.expectedCAFile, gotCAFile)
+			}
+
+			got := sources.describe("host", "port", "password", "password_file", "tls.ca_file", "tls.ca_pem")
+			if got != tc.expectedSources {
+				t.Errorf("Expected sources %q, got %q", tc.expectedSources, got)
+			}
+		})
+	}
+}
+
+func TestRequireSetting(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         types.String
+		expectedError string
+	}{
+		{name: "Set", value: types.StringValue("guardium.example.com")},
+		{name: "Unknown", value: types.StringUnknown(), expectedError: "Unknown Guardium Data Protection host"},
+		{name: "Missing", value: types.StringNull(), expectedError: "Missing Guardium Data Protection host"},
+		{name: "Empty", value: types.StringValue(""), expectedError: "Missing Guardium Data Protection host"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			requireSetting(tc.value, "host", envHost, &diags)
+			if tc.expectedError == "" {
+				if diags.HasError() {
+					t.Errorf("Expected no error but got: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || diags.Errors()[0].Summary() != tc.expectedError {
+				t.Fatalf("Expected error %q, got %v", tc.expectedError, diags)
+			}
+			if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, "`host`") || !strings.Contains(detail, envHost) {
+				t.Errorf("Expected the detail to name the attribute and the environment variable, got %q", detail)
+			}
+		})
+	}
+}
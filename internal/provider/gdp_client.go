@@ -0,0 +1,15 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// newGDPAPI returns a client that verifies the appliance certificate against caPath when it is set,
+// falling back to the provider `tls` settings when it is not
+func newGDPAPI(client gdp.API, caPath types.String) (gdp.API, error) {
+	return client.WithCAPath(caPath.ValueString())
+}
@@ -0,0 +1,221 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource              = &healthDataSource{}
+	_ datasource.DataSourceWithConfigure = &healthDataSource{}
+)
+
+// NewHealthDataSource is a helper function to simplify the provider implementation.
+func NewHealthDataSource() datasource.DataSource {
+	return &healthDataSource{}
+}
+
+// healthDataSource reports whether the appliance can be reached and used with the provider settings
+type healthDataSource struct {
+	client gdp.API
+}
+
+// healthDataSourceModel maps the data source schema data.
+type healthDataSourceModel struct {
+	AccessToken   types.String    `tfsdk:"access_token"`
+	CAPath        types.String    `tfsdk:"ca_path"`
+	Endpoint      types.String    `tfsdk:"endpoint"`
+	Reachable     types.Bool      `tfsdk:"reachable"`
+	LatencyMS     types.Int64     `tfsdk:"latency_ms"`
+	TLS           *healthTLSModel `tfsdk:"tls"`
+	Authenticated types.Bool      `tfsdk:"authenticated"`
+	User          types.String    `tfsdk:"user"`
+	Role          types.String    `tfsdk:"role"`
+	Version       types.String    `tfsdk:"version"`
+	Error         types.String    `tfsdk:"error"`
+}
+
+// healthTLSModel maps the `tls` attribute
+type healthTLSModel struct {
+	Version     types.String `tfsdk:"version"`
+	CipherSuite types.String `tfsdk:"cipher_suite"`
+	ServerName  types.String `tfsdk:"server_name"`
+	Subject     types.String `tfsdk:"subject"`
+	Issuer      types.String `tfsdk:"issuer"`
+	NotAfter    types.String `tfsdk:"not_after"`
+	Verified    types.Bool   `tfsdk:"verified"`
+}
+
+func (d *healthDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_health"
+}
+
+func (d *healthDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Checks in turn that the appliance is reachable, that it accepts the access token or the provider credentials, " +
+			"and which role and version it has. Failed checks are reported in `error` rather than failing the read, so that the result " +
+			"can be tested with a `postcondition` or an `output`",
+
+		Attributes: map[string]schema.Attribute{
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials. " +
+					"Without either only reachability is checked",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"ca_path": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
+				Optional:            true,
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "URL of the appliance, e.g. `https://guardium.example.com:8443`",
+				Computed:            true,
+			},
+			"reachable": schema.BoolAttribute{
+				MarkdownDescription: "Whether the appliance answered over HTTPS with the provider TLS settings",
+				Computed:            true,
+			},
+			"latency_ms": schema.Int64Attribute{
+				MarkdownDescription: "Time the appliance took to answer, in milliseconds",
+				Computed:            true,
+			},
+			"tls": schema.SingleNestedAttribute{
+				MarkdownDescription: "TLS connection to the appliance, unset when it is not reachable",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"version": schema.StringAttribute{
+						MarkdownDescription: "Negotiated TLS version, e.g. `TLS 1.3`",
+						Computed:            true,
+					},
+					"cipher_suite": schema.StringAttribute{
+						MarkdownDescription: "Negotiated cipher suite",
+						Computed:            true,
+					},
+					"server_name": schema.StringAttribute{
+						MarkdownDescription: "Server name the certificate was verified against, empty when connecting by IP address",
+						Computed:            true,
+					},
+					"subject": schema.StringAttribute{
+						MarkdownDescription: "Subject of the appliance certificate",
+						Computed:            true,
+					},
+					"issuer": schema.StringAttribute{
+						MarkdownDescription: "Issuer of the appliance certificate",
+						Computed:            true,
+					},
+					"not_after": schema.StringAttribute{
+						MarkdownDescription: "Expiry of the appliance certificate, in RFC 3339 format",
+						Computed:            true,
+					},
+					"verified": schema.BoolAttribute{
+						MarkdownDescription: "Whether the certificate was verified, `false` when `insecure_skip_verify` is set",
+						Computed:            true,
+					},
+				},
+			},
+			"authenticated": schema.BoolAttribute{
+				MarkdownDescription: "Whether the appliance accepted the access token",
+				Computed:            true,
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "User the provider credentials authenticate as, the OAuth client for grants without a user. " +
+					"Empty when `access_token` is set",
+				Computed: true,
+			},
+			"role": schema.StringAttribute{
+				MarkdownDescription: "Role of the appliance, one of `collector`, `aggregator`, `central_manager` or `unknown`",
+				Computed:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Version and patch level, e.g. `12.1 patch 100`. Empty when the appliance does not report it",
+				Computed:            true,
+			},
+			"error": schema.StringAttribute{
+				MarkdownDescription: "Why the first failed check failed, empty when every check passed",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *healthDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(gdp.API)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected gdp.API, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *healthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data healthDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, err := newGDPAPI(d.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	health, err := c.Health(ctx, data.AccessToken.ValueString())
+	if gdp.IsCanceled(err) {
+		addClientError(&resp.Diagnostics, "Failed to check appliance health", err.Error(), err)
+		return
+	}
+
+	data.Error = types.StringValue("")
+	if err != nil {
+		data.Error = types.StringValue(err.Error())
+	}
+	data.Endpoint = types.StringValue(health.Endpoint)
+	data.Reachable = types.BoolValue(health.Reachable)
+	data.LatencyMS = types.Int64Value(health.Latency.Milliseconds())
+	data.TLS = nil
+	if health.TLS != nil {
+		data.TLS = &healthTLSModel{
+			Version:     types.StringValue(health.TLS.Version),
+			CipherSuite: types.StringValue(health.TLS.CipherSuite),
+			ServerName:  types.StringValue(health.TLS.ServerName),
+			Subject:     types.StringValue(health.TLS.Subject),
+			Issuer:      types.StringValue(health.TLS.Issuer),
+			NotAfter:    types.StringValue(health.TLS.NotAfter.Format(time.RFC3339)),
+			Verified:    types.BoolValue(health.TLS.Verified),
+		}
+	}
+	data.Authenticated = types.BoolValue(health.Authenticated)
+	data.User = types.StringValue(health.User)
+	data.Role = types.StringValue(string(health.Role))
+	data.Version = types.StringValue("")
+	if health.Version != nil {
+		data.Version = types.StringValue(health.Version.String())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
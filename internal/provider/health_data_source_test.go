@@ -0,0 +1,100 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccHealthDataSource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: appliance.config(`
+data "guardium-data-protection_health" "test" {}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "reachable", "true"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "authenticated", "true"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "user", appliance.Username),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "role", "collector"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "version", "12.1 patch 100"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "tls.verified", "true"),
+					resource.TestCheckResourceAttrSet("data.guardium-data-protection_health.test", "tls.not_after"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "error", ""),
+				),
+			},
+			{
+				// A rejected access token is reported rather than failing the read
+				Config: appliance.config(`
+data "guardium-data-protection_health" "test" {
+  access_token = "invalid-token"
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "reachable", "true"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "authenticated", "false"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "role", "unknown"),
+					resource.TestMatchResourceAttr("data.guardium-data-protection_health.test", "error", regexp.MustCompile(`could not authenticate`)),
+				),
+			},
+		},
+	})
+}
+
+// testAccPreflightConfig returns the provider block of appliance with preflight enabled, a single attempt per
+// request and each of replacements applied, followed by a health data source
+func testAccPreflightConfig(appliance *testAccAppliance, replacements ...string) string {
+	providerConfig := strings.NewReplacer(replacements...).Replace(appliance.ProviderConfig)
+	providerConfig = strings.Replace(providerConfig, "  tls {", "  preflight = true\n\n  retry {\n    max_attempts = 1\n  }\n\n  tls {", 1)
+	return providerConfig + `
+data "guardium-data-protection_health" "test" {}
+`
+}
+
+func TestAccProviderPreflight(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	// Start and immediately stop a server to get a port nothing listens on
+	closed := httptest.NewServer(nil)
+	closed.Close()
+	closedURL, _ := url.Parse(closed.URL)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPreflightConfig(appliance, fmt.Sprintf("password      = %q", appliance.Password), `password      = "wrong"`),
+				ExpectError: regexp.MustCompile(`Appliance rejected the provider credentials`),
+			},
+			{
+				Config:      testAccPreflightConfig(appliance, fmt.Sprintf("ca_file = %q", appliance.CAFile), `min_version = "1.2"`),
+				ExpectError: regexp.MustCompile(`Untrusted appliance certificate`),
+			},
+			{
+				Config:      testAccPreflightConfig(appliance, fmt.Sprintf("port          = %q", appliance.Port), fmt.Sprintf("port          = %q", closedURL.Port())),
+				ExpectError: regexp.MustCompile(`Appliance unreachable`),
+			},
+			{
+				// The appliance manages no units, which does not make the target unit invalid
+				Config:      testAccPreflightConfig(appliance, "  tls {", "  target_unit = \"mu1\"\n\n  tls {"),
+				ExpectError: regexp.MustCompile(`Failed to verify target unit`),
+			},
+			{
+				Config: testAccPreflightConfig(appliance),
+				Check:  resource.TestCheckResourceAttr("data.guardium-data-protection_health.test", "authenticated", "true"),
+			},
+		},
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,18 +16,25 @@ import (
 	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
 )
 
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &ImportProfilesResource{}
+	_ resource.ResourceWithConfigure = &ImportProfilesResource{}
+)
+
 // ImportProfilesResource defines the resource implementation
 type ImportProfilesResource struct {
-	client *gdp.Client
+	client gdp.API
 }
 
 // ImportProfilesResourceModel describes the resource data model
 type ImportProfilesResourceModel struct {
-	AccessToken types.String `tfsdk:"access_token"`
-	PathToFile  types.String `tfsdk:"path_to_file"`
-	UpdateMode  types.Bool   `tfsdk:"update_mode"`
-	ID          types.String `tfsdk:"id"`
-	CaPath      types.String `tfsdk:"ca_path"`
+	AccessToken types.String   `tfsdk:"access_token"`
+	PathToFile  types.String   `tfsdk:"path_to_file"`
+	UpdateMode  types.Bool     `tfsdk:"update_mode"`
+	ID          types.String   `tfsdk:"id"`
+	CaPath      types.String   `tfsdk:"ca_path"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func NewImportProfilesResource() resource.Resource {
@@ -39,21 +47,23 @@ func (r *ImportProfilesResource) Metadata(_ context.Context, req resource.Metada
 }
 
 // Schema defines the schema for the resource
-func (r *ImportProfilesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *ImportProfilesResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Import profiles from a file",
+		MarkdownDescription: "Import profiles from a file. The appliance keeps no record of imports that could be read back, so the resource " +
+			"cannot be imported, and destroying it leaves the imported profiles in place",
 		Attributes: map[string]schema.Attribute{
 			"access_token": schema.StringAttribute{
-				MarkdownDescription: "Access token for authentication",
-				Required:            true,
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
 				Sensitive:           true,
 			},
 			"path_to_file": schema.StringAttribute{
-				MarkdownDescription: "Path to the file to import",
-				Required:            true,
+				MarkdownDescription: "Path to the file to import. A file present on the machine running Terraform is uploaded, " +
+					"otherwise the path is read on the appliance itself",
+				Required: true,
 			},
 			"ca_path": schema.StringAttribute{
-				MarkdownDescription: "Path to the file to import",
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
 				Optional:            true,
 			},
 			"update_mode": schema.BoolAttribute{
@@ -68,6 +78,9 @@ func (r *ImportProfilesResource) Schema(_ context.Context, _ resource.SchemaRequ
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -77,9 +90,9 @@ func (r *ImportProfilesResource) Configure(ctx context.Context, req resource.Con
 		return
 	}
 
-	client, ok := req.ProviderData.(*gdp.Client)
+	client, ok := req.ProviderData.(gdp.API)
 	if !ok {
-		resp.Diagnostics.AddError("Unexpected Resource Configure Type", fmt.Sprintf("Expected *gdp.Client, got: %T", req.ProviderData))
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type", fmt.Sprintf("Expected gdp.API, got: %T", req.ProviderData))
 		return
 	}
 
@@ -95,16 +108,25 @@ func (r *ImportProfilesResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	if data.CaPath.IsNull() {
-		c := r.client.NewInsecureClient()
-		if err := c.ImportProfilesFromFile(ctx, data.AccessToken.ValueString(), data.PathToFile.ValueString(), data.UpdateMode.ValueBool()); err != nil {
-			resp.Diagnostics.AddError("Error importing profiles", fmt.Sprintf("Could not import profiles: %s", err))
-			return
-		}
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, err := newGDPAPI(r.client, data.CaPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority", fmt.Sprintf("Could not load certificate authority: %s", err))
+		return
+	}
+
+	if err := c.ImportProfilesFromFile(ctx, data.AccessToken.ValueString(), data.PathToFile.ValueString(), data.UpdateMode.ValueBool()); err != nil {
+		addClientError(&resp.Diagnostics, "Error importing profiles", fmt.Sprintf("Could not import profiles: %s", err), err)
+		return
 	}
 
 	// Set a unique ID for the resource
-	data.ID = types.StringValue(fmt.Sprintf("%s-%s", r.client.Host, data.PathToFile.ValueString()))
+	data.ID = types.StringValue(fmt.Sprintf("%s-%s", r.client.Host(), data.PathToFile.ValueString()))
 
 	// Set state
 	diags = resp.State.Set(ctx, &data)
@@ -135,15 +157,26 @@ func (r *ImportProfilesResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	if data.CaPath.IsNull() {
-		c := r.client.NewInsecureClient()
-		if err := c.ImportProfilesFromFile(ctx, data.AccessToken.ValueString(), data.PathToFile.ValueString(), data.UpdateMode.ValueBool()); err != nil {
-			resp.Diagnostics.AddError(
-				"Error importing profiles",
-				fmt.Sprintf("Could not import profiles: %s", err),
-			)
-			return
-		}
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, err := newGDPAPI(r.client, data.CaPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority", fmt.Sprintf("Could not load certificate authority: %s", err))
+		return
+	}
+
+	if err := c.ImportProfilesFromFile(ctx, data.AccessToken.ValueString(), data.PathToFile.ValueString(), data.UpdateMode.ValueBool()); err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Error importing profiles",
+			fmt.Sprintf("Could not import profiles: %s", err),
+			err,
+		)
+		return
 	}
 
 	// Set state
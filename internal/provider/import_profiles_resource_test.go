@@ -0,0 +1,148 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+func TestImportProfilesResourceCreate(t *testing.T) {
+	fake := gdptest.NewFake()
+	model := ImportProfilesResourceModel{
+		AccessToken: types.StringNull(),
+		PathToFile:  types.StringValue("/var/dump/profiles.csv"),
+		UpdateMode:  types.BoolValue(true),
+		ID:          types.StringUnknown(),
+		CaPath:      types.StringNull(),
+		Timeouts:    nullTimeouts(),
+	}
+	resp := createResource(t, NewImportProfilesResource(), fake, &model)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected create diagnostics: %v", resp.Diagnostics)
+	}
+
+	expected := []gdptest.ImportedProfiles{{PathToFile: "/var/dump/profiles.csv", UpdateMode: true}}
+	if fmt.Sprint(fake.ImportedProfiles) != fmt.Sprint(expected) {
+		t.Errorf("Expected %+v to be imported, got %+v", expected, fake.ImportedProfiles)
+	}
+	var created ImportProfilesResourceModel
+	resp.State.Get(context.Background(), &created)
+	if created.ID.ValueString() != "guardium.example.com-/var/dump/profiles.csv" {
+		t.Errorf("Expected the ID to name the appliance and the file, got %s", created.ID)
+	}
+
+	// Without provider credentials nor access token nothing is imported
+	fake = &gdptest.Fake{}
+	resp = createResource(t, NewImportProfilesResource(), fake, &model)
+	if !resp.Diagnostics.HasError() || len(fake.ImportedProfiles) != 0 {
+		t.Errorf("Expected the import to fail without an access token, got %v and %+v", resp.Diagnostics, fake.ImportedProfiles)
+	}
+}
+
+func testAccImportProfilesConfig(appliance *testAccAppliance, pathToFile string, updateMode bool) string {
+	return appliance.config(`
+resource "guardium-data-protection_import_profiles" "test" {
+  path_to_file = %q
+  update_mode  = %t
+  ca_path      = %q
+}
+`, pathToFile, updateMode, appliance.CAFile)
+}
+
+// testAccProfilesFile writes a profiles file to upload
+func testAccProfilesFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "profiles.csv")
+	if err := os.WriteFile(path, []byte("name,type\nudc,Oracle\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAccImportProfilesResource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+	path := testAccProfilesFile(t)
+
+	// There is no import step: imports cannot be read back from the appliance
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// Destroying only forgets the import, nothing is sent to the appliance
+		CheckDestroy: func(*terraform.State) error {
+			if profiles := appliance.UploadedProfiles(); len(profiles) != 3 {
+				return fmt.Errorf("expected the imports to be left alone, got %+v", profiles)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImportProfilesConfig(appliance, path, false),
+				Check: func(*terraform.State) error {
+					profiles := appliance.UploadedProfiles()
+					if len(profiles) != 1 || profiles[0].PathToFile != "profiles.csv" || profiles[0].UpdateMode {
+						return fmt.Errorf("expected the file to be uploaded once, got %+v", profiles)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccImportProfilesConfig(appliance, path, true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("guardium-data-protection_import_profiles.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(*terraform.State) error {
+					profiles := appliance.UploadedProfiles()
+					if len(profiles) != 2 || !profiles[1].UpdateMode {
+						return fmt.Errorf("expected the file to be imported again in update mode, got %+v", profiles)
+					}
+					return nil
+				},
+			},
+			{
+				// Files that are not on the machine running Terraform are read on the appliance
+				Config: testAccImportProfilesConfig(appliance, "/var/dump/profiles.csv", true),
+				Check: func(*terraform.State) error {
+					profiles := appliance.UploadedProfiles()
+					if len(profiles) != 3 || profiles[2].PathToFile != "/var/dump/profiles.csv" || profiles[2].Content != nil {
+						return fmt.Errorf("expected the appliance path to be imported, got %+v", profiles)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccImportProfilesResourceUnknownVersion(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+	// The appliance does not report its version, which does not keep the file from being uploaded
+	appliance.Version = ""
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImportProfilesConfig(appliance, testAccProfilesFile(t), true),
+				Check: func(*terraform.State) error {
+					if profiles := appliance.UploadedProfiles(); len(profiles) != 1 || profiles[0].Content == nil {
+						return fmt.Errorf("expected the file to be uploaded, got %+v", profiles)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,25 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// decodeImportID decodes an import ID holding a JSON object into v. Resources whose settings the appliance does
+// not report are imported with those settings instead of a plain identifier.
+func decodeImportID(id string, v any, diags *diag.Diagnostics) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(id)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		diags.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("The appliance does not report this configuration, import it with its settings as a JSON object: %s.", err),
+		)
+	}
+}
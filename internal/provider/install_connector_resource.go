@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -15,16 +16,18 @@ import (
 
 // InstallConnectorResource defines the resource implementation
 type InstallConnectorResource struct {
-	client *gdp.Client
+	client gdp.API
 }
 
 // InstallConnectorResourceModel describes the resource data model
 type InstallConnectorResourceModel struct {
-	AccessToken types.String `tfsdk:"access_token"`
-	CAPath      types.String `tfsdk:"ca_path"`
-	UdcName     types.String `tfsdk:"udc_name"`
-	GdpMuHost   types.String `tfsdk:"gdp_mu_host"`
-	ID          types.String `tfsdk:"id"`
+	AccessToken types.String   `tfsdk:"access_token"`
+	CAPath      types.String   `tfsdk:"ca_path"`
+	UdcName     types.String   `tfsdk:"udc_name"`
+	GdpMuHost   types.String   `tfsdk:"gdp_mu_host"`
+	ID          types.String   `tfsdk:"id"`
+	TargetUnit  types.String   `tfsdk:"target_unit"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func NewInstallConnectorResource() resource.Resource {
@@ -37,17 +40,18 @@ func (r *InstallConnectorResource) Metadata(_ context.Context, req resource.Meta
 }
 
 // Schema defines the schema for the resource
-func (r *InstallConnectorResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *InstallConnectorResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Install connector in bulk",
+		MarkdownDescription: "Install connector in bulk. The appliance keeps no record of installs that could be read back, so the resource " +
+			"cannot be imported, and destroying it leaves the connector installed",
 		Attributes: map[string]schema.Attribute{
 			"access_token": schema.StringAttribute{
-				MarkdownDescription: "Access token for authentication",
-				Required:            true,
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
 				Sensitive:           true,
 			},
 			"ca_path": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection server certificate authority path",
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
 				Optional:            true,
 			},
 			"udc_name": schema.StringAttribute{
@@ -62,6 +66,10 @@ func (r *InstallConnectorResource) Schema(_ context.Context, _ resource.SchemaRe
 				Computed:            true,
 				MarkdownDescription: "Resource identifier",
 			},
+			"target_unit": targetUnitAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -72,11 +80,11 @@ func (r *InstallConnectorResource) Configure(ctx context.Context, req resource.C
 		return
 	}
 
-	client, ok := req.ProviderData.(*gdp.Client)
+	client, ok := req.ProviderData.(gdp.API)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *gdp.Client, got: %T", req.ProviderData),
+			fmt.Sprintf("Expected gdp.API, got: %T", req.ProviderData),
 		)
 		return
 	}
@@ -93,21 +101,33 @@ func (r *InstallConnectorResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	if data.CAPath.IsNull() {
-
-		// Make the API call to install connector
-		err := r.client.NewInsecureClient().BulkInstallConnector(ctx, data.AccessToken.ValueString(), data.UdcName.ValueString(), data.GdpMuHost.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error installing connector",
-				fmt.Sprintf("Could not install connector: %s", err),
-			)
-			return
-		}
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority", fmt.Sprintf("Could not load certificate authority: %s", err))
+		return
+	}
+
+	// Make the API call to install connector
+	err = c.BulkInstallConnector(ctx, data.AccessToken.ValueString(), data.UdcName.ValueString(), data.GdpMuHost.ValueString())
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Error installing connector",
+			fmt.Sprintf("Could not install connector: %s", err),
+			err,
+		)
+		return
 	}
 
 	// Set a unique ID for the resource
-	data.ID = types.StringValue(fmt.Sprintf("%s-%s-%s", r.client.Host, data.UdcName.ValueString(), data.GdpMuHost.ValueString()))
+	data.ID = types.StringValue(fmt.Sprintf("%s-%s-%s", r.client.Host(), data.UdcName.ValueString(), data.GdpMuHost.ValueString()))
 
 	// Set state
 	diags = resp.State.Set(ctx, &data)
@@ -138,15 +158,29 @@ func (r *InstallConnectorResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	if data.CAPath.IsNull() {
-		// Make the API call to install connector
-		err := r.client.NewInsecureClient().BulkInstallConnector(ctx, data.AccessToken.ValueString(), data.UdcName.ValueString(), data.GdpMuHost.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("Error installing connector", fmt.Sprintf("Could not install connector: %s", err))
-			return
-		}
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority", fmt.Sprintf("Could not load certificate authority: %s", err))
+		return
 	}
 
+	// Make the API call to install connector
+	err = c.BulkInstallConnector(ctx, data.AccessToken.ValueString(), data.UdcName.ValueString(), data.GdpMuHost.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Error installing connector", fmt.Sprintf("Could not install connector: %s", err), err)
+		return
+	}
+
+	// The ID is unknown in the plan and follows the connector and host
+	data.ID = types.StringValue(fmt.Sprintf("%s-%s-%s", r.client.Host(), data.UdcName.ValueString(), data.GdpMuHost.ValueString()))
+
 	// Set state
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
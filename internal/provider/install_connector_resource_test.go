@@ -0,0 +1,111 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+func TestInstallConnectorResourceCreate(t *testing.T) {
+	fake := gdptest.NewFake()
+	fake.HostName = "cm.example.com"
+	model := InstallConnectorResourceModel{
+		AccessToken: types.StringNull(),
+		CAPath:      types.StringNull(),
+		UdcName:     types.StringValue("udc-oracle"),
+		GdpMuHost:   types.StringValue("mu1.example.com"),
+		ID:          types.StringUnknown(),
+		TargetUnit:  types.StringNull(),
+		Timeouts:    nullTimeouts(),
+	}
+	resp := createResource(t, NewInstallConnectorResource(), fake, &model)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected create diagnostics: %v", resp.Diagnostics)
+	}
+
+	expected := []gdptest.InstalledConnector{{UDCName: "udc-oracle", GdpMuHost: "mu1.example.com"}}
+	if fmt.Sprint(fake.InstalledConnectors) != fmt.Sprint(expected) {
+		t.Errorf("Expected %+v to be installed, got %+v", expected, fake.InstalledConnectors)
+	}
+	var created InstallConnectorResourceModel
+	resp.State.Get(context.Background(), &created)
+	if created.ID.ValueString() != "cm.example.com-udc-oracle-mu1.example.com" {
+		t.Errorf("Expected the ID to name the appliance, profile and host, got %s", created.ID)
+	}
+
+	fake.SetError("BulkInstallConnector", &gdp.APIError{StatusCode: 500, Endpoint: "POST /restAPI/bulkInstall"})
+	resp = createResource(t, NewInstallConnectorResource(), fake, &model)
+	if !resp.Diagnostics.HasError() || len(fake.InstalledConnectors) != 1 {
+		t.Errorf("Expected the failed install to be reported, got %v and %+v", resp.Diagnostics, fake.InstalledConnectors)
+	}
+}
+
+func testAccInstallConnectorConfig(appliance *testAccAppliance, udcName, gdpMuHost string) string {
+	return appliance.config(`
+resource "guardium-data-protection_install_connector" "test" {
+  udc_name    = %q
+  gdp_mu_host = %q
+  ca_path     = %q
+  target_unit = "mu1"
+}
+`, udcName, gdpMuHost, appliance.CAFile)
+}
+
+func TestAccInstallConnectorResource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+	appliance.ManagedUnits = []gdp.ManagedUnit{
+		{Name: "mu1", Host: "mu1.example.com", Online: true},
+		{Name: "mu2", Host: "mu2.example.com", Online: true},
+	}
+
+	// There is no import step: installs cannot be read back from the appliance
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// Destroying only forgets the install, nothing is sent to the appliance
+		CheckDestroy: func(*terraform.State) error {
+			if connectors := appliance.InstalledConnectors(); len(connectors) != 2 {
+				return fmt.Errorf("expected the installs to be left alone, got %+v", connectors)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstallConnectorConfig(appliance, "udc-oracle", "mu1.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("guardium-data-protection_install_connector.test", "id"),
+					func(*terraform.State) error {
+						expected := []gdptest.InstalledConnector{{UDCName: "udc-oracle", GdpMuHost: "mu1.example.com"}}
+						if connectors := appliance.InstalledConnectors(); fmt.Sprint(connectors) != fmt.Sprint(expected) {
+							return fmt.Errorf("expected %+v to be installed, got %+v", expected, connectors)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccInstallConnectorConfig(appliance, "udc-oracle", "mu2.example.com"),
+				Check: func(*terraform.State) error {
+					if connectors := appliance.InstalledConnectors(); len(connectors) != 2 || connectors[1].GdpMuHost != "mu2.example.com" {
+						return fmt.Errorf("expected the connector to be installed on mu2, got %+v", connectors)
+					}
+					return nil
+				},
+			},
+			{
+				Config:      testAccInstallConnectorConfig(appliance, "udc-oracle", "unknown.example.com"),
+				ExpectError: regexp.MustCompile(`One or more of the specified hosts could not be found`),
+			},
+		},
+	})
+}
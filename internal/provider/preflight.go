@@ -0,0 +1,56 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// connectionAttributes are the provider attributes deciding how the appliance is reached
+var connectionAttributes = []string{"host", "port", "tls.ca_file", "tls.ca_pem"}
+
+// preflight checks the appliance health with the provider settings, reporting the first failed check with the
+// settings that caused it so that misconfigurations fail Configure instead of every resource
+func preflight(ctx context.Context, api gdp.API, sources settingSources, diags *diag.Diagnostics) {
+	health, err := api.Health(ctx, "")
+	var certErr *tls.CertificateVerificationError
+	switch {
+	case err == nil:
+		fields := map[string]any{"endpoint": health.Endpoint, "role": string(health.Role), "user": health.User, "latency": health.Latency.String()}
+		if health.Version != nil {
+			fields["version"] = health.Version.String()
+		}
+		tflog.Info(ctx, "preflight check passed", fields)
+	case gdp.IsCanceled(err):
+		addClientError(diags, "Preflight check interrupted", err.Error(), err)
+	case errors.As(err, &certErr):
+		diags.AddError(
+			"Untrusted appliance certificate",
+			fmt.Sprintf("Preflight check could not verify the certificate of %s: %s. Configure the certificate authority that issued it "+
+				"with `tls.ca_file` or `tls.ca_pem`. Settings were read from: %s.", health.Endpoint, err, sources.describe(connectionAttributes...)),
+		)
+	case !health.Reachable:
+		diags.AddError(
+			"Appliance unreachable",
+			fmt.Sprintf("Preflight check could not reach %s: %s. Check `host`, `port` and `proxy_url`. Settings were read from: %s.",
+				health.Endpoint, err, sources.describe(connectionAttributes...)),
+		)
+	case !health.Authenticated:
+		diags.AddError(
+			"Appliance rejected the provider credentials",
+			fmt.Sprintf("Preflight check could not authenticate to %s: %s. Credentials were read from: %s.",
+				health.Endpoint, err, sources.describe(credentialAttributes...)),
+		)
+	default:
+		diags.AddError("Preflight check failed", fmt.Sprintf("Preflight check of %s failed: %s.", health.Endpoint, err))
+	}
+}
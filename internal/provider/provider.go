@@ -5,11 +5,17 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
 )
@@ -18,6 +24,7 @@ import (
 // these are unused functions and are purely defined for the debugging to ensure we are
 // satisfying all interfaces correctly
 var _ provider.Provider = &GuardiumDataProtectionProvider{}
+var _ provider.ProviderWithValidateConfig = &GuardiumDataProtectionProvider{}
 
 // GuardiumDataProtectionProvider defines the provider implementation.
 type GuardiumDataProtectionProvider struct {
@@ -28,8 +35,192 @@ type GuardiumDataProtectionProvider struct {
 }
 
 type guardiumDataProtectionModel struct {
-	Host string `tfsdk:"host"`
-	Port string `tfsdk:"port"`
+	Host              types.String    `tfsdk:"host"`
+	Port              types.String    `tfsdk:"port"`
+	ClientID          types.String    `tfsdk:"client_id"`
+	ClientSecret      types.String    `tfsdk:"client_secret"`
+	Username          types.String    `tfsdk:"username"`
+	Password          types.String    `tfsdk:"password"`
+	GrantType         types.String    `tfsdk:"grant_type"`
+	RefreshToken      types.String    `tfsdk:"refresh_token"`
+	ClientAuth        types.String    `tfsdk:"client_auth"`
+	PasswordFile      types.String    `tfsdk:"password_file"`
+	ClientSecretFile  types.String    `tfsdk:"client_secret_file"`
+	CredentialProcess types.String    `tfsdk:"credential_process"`
+	ProxyURL          types.String    `tfsdk:"proxy_url"`
+	NoProxy           types.String    `tfsdk:"no_proxy"`
+	ExtraHeaders      types.Map       `tfsdk:"extra_headers"`
+	RequestTimeout    types.String    `tfsdk:"request_timeout"`
+	TargetUnit        types.String    `tfsdk:"target_unit"`
+	Preflight         types.Bool      `tfsdk:"preflight"`
+	TLS               *tlsModel       `tfsdk:"tls"`
+	Retry             *retryModel     `tfsdk:"retry"`
+	RateLimit         *rateLimitModel `tfsdk:"rate_limit"`
+}
+
+// credentialAttributes are the provider attributes used to obtain an access token
+var credentialAttributes = []string{
+	"grant_type", "client_id", "client_secret", "username", "password", "refresh_token", "client_auth",
+	"password_file", "client_secret_file", "credential_process",
+}
+
+// credentialValues returns the values of credentialAttributes, in the same order
+func (m *guardiumDataProtectionModel) credentialValues() []types.String {
+	return []types.String{
+		m.GrantType, m.ClientID, m.ClientSecret, m.Username, m.Password, m.RefreshToken, m.ClientAuth,
+		m.PasswordFile, m.ClientSecretFile, m.CredentialProcess,
+	}
+}
+
+// gdpCredentials returns the provider credentials, or nil when none are configured
+func (m *guardiumDataProtectionModel) gdpCredentials() *gdp.Credentials {
+	configured := false
+	for _, value := range m.credentialValues() {
+		configured = configured || !value.IsNull()
+	}
+	if !configured {
+		return nil
+	}
+
+	return &gdp.Credentials{
+		GrantType:    m.GrantType.ValueString(),
+		ClientID:     m.ClientID.ValueString(),
+		ClientSecret: m.ClientSecret.ValueString(),
+		Username:     m.Username.ValueString(),
+		Password:     m.Password.ValueString(),
+		RefreshToken: m.RefreshToken.ValueString(),
+		ClientAuth:   m.ClientAuth.ValueString(),
+
+		PasswordFile:     m.PasswordFile.ValueString(),
+		ClientSecretFile: m.ClientSecretFile.ValueString(),
+		Process:          m.CredentialProcess.ValueString(),
+	}
+}
+
+// credentialsKnown reports whether every credential value is known, so that the credentials can be validated and used
+func (m *guardiumDataProtectionModel) credentialsKnown() bool {
+	for _, value := range m.credentialValues() {
+		if value.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// tlsModel maps the provider `tls` block
+type tlsModel struct {
+	CAPEM              types.String `tfsdk:"ca_pem"`
+	CAFile             types.String `tfsdk:"ca_file"`
+	ServerName         types.String `tfsdk:"server_name"`
+	MinVersion         types.String `tfsdk:"min_version"`
+	ClientCertPEM      types.String `tfsdk:"client_cert_pem"`
+	ClientCertFile     types.String `tfsdk:"client_cert_file"`
+	ClientKeyPEM       types.String `tfsdk:"client_key_pem"`
+	ClientKeyFile      types.String `tfsdk:"client_key_file"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+}
+
+// gdpTLSConfig converts the `tls` block into the gdp client TLS settings
+func (t *tlsModel) gdpTLSConfig() gdp.TLSConfig {
+	if t == nil {
+		return gdp.TLSConfig{}
+	}
+
+	return gdp.TLSConfig{
+		CAPEM:              t.CAPEM.ValueString(),
+		CAFile:             t.CAFile.ValueString(),
+		ServerName:         t.ServerName.ValueString(),
+		MinVersion:         t.MinVersion.ValueString(),
+		ClientCertPEM:      t.ClientCertPEM.ValueString(),
+		ClientCertFile:     t.ClientCertFile.ValueString(),
+		ClientKeyPEM:       t.ClientKeyPEM.ValueString(),
+		ClientKeyFile:      t.ClientKeyFile.ValueString(),
+		InsecureSkipVerify: t.InsecureSkipVerify.ValueBool(),
+	}
+}
+
+// retryModel maps the provider `retry` block
+type retryModel struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	MinBackoff  types.String `tfsdk:"min_backoff"`
+	MaxBackoff  types.String `tfsdk:"max_backoff"`
+}
+
+// gdpRetryConfig converts the `retry` block into the gdp client retry settings, starting from the defaults
+func (r *retryModel) gdpRetryConfig() (gdp.RetryConfig, error) {
+	config := gdp.DefaultRetryConfig()
+	if r == nil {
+		return config, nil
+	}
+
+	if !r.MaxAttempts.IsNull() {
+		config.MaxAttempts = int(r.MaxAttempts.ValueInt64())
+	}
+
+	var err error
+	if !r.MinBackoff.IsNull() {
+		if config.MinBackoff, err = time.ParseDuration(r.MinBackoff.ValueString()); err != nil {
+			return config, fmt.Errorf("invalid min_backoff: %w", err)
+		}
+	}
+	if !r.MaxBackoff.IsNull() {
+		if config.MaxBackoff, err = time.ParseDuration(r.MaxBackoff.ValueString()); err != nil {
+			return config, fmt.Errorf("invalid max_backoff: %w", err)
+		}
+	}
+
+	if config.MaxAttempts < 1 {
+		return config, fmt.Errorf("max_attempts must be at least 1")
+	}
+	if config.MinBackoff < 0 || config.MaxBackoff < config.MinBackoff {
+		return config, fmt.Errorf("min_backoff must not be negative and max_backoff must not be lower than min_backoff")
+	}
+
+	return config, nil
+}
+
+// parseRequestTimeout parses the `request_timeout` attribute, defaulting to gdp.DefaultRequestTimeout
+func parseRequestTimeout(value types.String) (time.Duration, error) {
+	if value.IsNull() || value.IsUnknown() {
+		return gdp.DefaultRequestTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		return 0, fmt.Errorf("invalid request_timeout: %w", err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("request_timeout must not be negative")
+	}
+
+	return timeout, nil
+}
+
+// rateLimitModel maps the provider `rate_limit` block
+type rateLimitModel struct {
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	Burst                 types.Int64   `tfsdk:"burst"`
+}
+
+// gdpRateLimitConfig converts the `rate_limit` block into the gdp client limits, starting from the defaults
+func (r *rateLimitModel) gdpRateLimitConfig() (gdp.RateLimitConfig, error) {
+	config := gdp.DefaultRateLimitConfig()
+	if r == nil {
+		return config, nil
+	}
+
+	if !r.MaxConcurrentRequests.IsNull() {
+		config.MaxConcurrent = int(r.MaxConcurrentRequests.ValueInt64())
+	}
+	if !r.RequestsPerSecond.IsNull() {
+		config.RequestsPerSecond = r.RequestsPerSecond.ValueFloat64()
+	}
+	if !r.Burst.IsNull() {
+		config.Burst = int(r.Burst.ValueInt64())
+	}
+
+	return config, config.Validate()
 }
 
 func (p *GuardiumDataProtectionProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -41,17 +232,300 @@ func (p *GuardiumDataProtectionProvider) Schema(ctx context.Context, req provide
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				MarkdownDescription: "The Guardium Data Protection host",
-				Required:            true,
+				MarkdownDescription: "The Guardium Data Protection host. Defaults to the `GUARDIUM_HOST` environment variable",
+				Optional:            true,
 			},
 			"port": schema.StringAttribute{
-				MarkdownDescription: "The Guardium Data Protection host",
-				Required:            true,
+				MarkdownDescription: "The Guardium Data Protection port. Defaults to the `GUARDIUM_PORT` environment variable",
+				Optional:            true,
+			},
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "Guardium Data Protection OAuth client id. When the provider credentials are set, " +
+					"resources obtain their access token automatically and `access_token` can be omitted. Defaults to the `GUARDIUM_CLIENT_ID` environment variable",
+				Optional: true,
+			},
+			"client_secret": schema.StringAttribute{
+				MarkdownDescription: "Guardium Data Protection OAuth client secret. Defaults to the `GUARDIUM_CLIENT_SECRET` environment variable",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Guardium Data Protection username, required by the `password` grant. Defaults to the `GUARDIUM_USERNAME` environment variable",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Guardium Data Protection password, required by the `password` grant. Defaults to the `GUARDIUM_PASSWORD` environment variable",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"password_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file holding the Guardium Data Protection password, read every time an access token is requested. Conflicts with `password`",
+				Optional:            true,
+			},
+			"client_secret_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file holding the OAuth client secret, read every time an access token is requested. Conflicts with `client_secret`",
+				Optional:            true,
+			},
+			"credential_process": schema.StringAttribute{
+				MarkdownDescription: "Command run through the system shell every time an access token is requested, which prints a JSON object " +
+					"with any of `client_id`, `client_secret`, `username` and `password` on its standard output. " +
+					"Values set by other attributes, files or environment variables take precedence over the ones it prints",
+				Optional: true,
+			},
+			"grant_type": schema.StringAttribute{
+				MarkdownDescription: "OAuth grant used to obtain access tokens: `password`, `client_credentials` for service accounts " +
+					"without a user password, or `refresh_token`. Defaults to `password`",
+				Optional: true,
+			},
+			"refresh_token": schema.StringAttribute{
+				MarkdownDescription: "OAuth refresh token, required by the `refresh_token` grant",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_auth": schema.StringAttribute{
+				MarkdownDescription: "How the OAuth client authenticates to the token endpoint: `body` sends `client_id` and `client_secret` " +
+					"in the form encoded request body, `basic` sends them as HTTP basic authentication. Defaults to `body`",
+				Optional: true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the proxy used to reach the Guardium Data Protection host, e.g. `http://proxy.example.com:3128`. " +
+					"Supports the `http`, `https` and `socks5` schemes. Defaults to the `HTTPS_PROXY` environment variable",
+				Optional: true,
+			},
+			"no_proxy": schema.StringAttribute{
+				MarkdownDescription: "Comma separated hosts, domains and CIDR ranges reached without the proxy, using the `NO_PROXY` syntax. " +
+					"Defaults to the `NO_PROXY` environment variable",
+				Optional: true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration of a single call to the Guardium Data Protection host including its retries, " +
+					"such as `90s` or `10m`. `0s` disables the limit. Resource operations are additionally bounded by their `timeouts` block. Defaults to `5m`",
+				Optional: true,
+			},
+			"extra_headers": schema.MapAttribute{
+				MarkdownDescription: "Headers added to every request sent to the Guardium Data Protection host, such as a correlation header " +
+					"required by a gateway. Headers set by the provider itself, like `Authorization`, are not overridden",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"target_unit": schema.StringAttribute{
+				MarkdownDescription: "Managed unit, by name, host name or IP address, that the Central Manager set as `host` runs commands on. " +
+					"The unit must be registered with the Central Manager. Resources can override it with their own `target_unit`. " +
+					"When omitted commands run on `host` itself",
+				Optional: true,
+			},
+			"preflight": schema.BoolAttribute{
+				MarkdownDescription: "Check when the provider is configured that `host` is reachable with the `tls` settings and accepts the provider " +
+					"credentials, and that the Central Manager manages `target_unit`, so that misconfigurations fail up front with the setting at fault " +
+					"rather than in every resource. Credentials not known until apply are checked then. Without it the appliance is only contacted " +
+					"by resources and data sources. Defaults to `false`",
+				Optional: true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"tls": schema.SingleNestedBlock{
+				MarkdownDescription: "TLS settings used to connect to the Guardium Data Protection host. " +
+					"When omitted the appliance certificate is verified against the system certificate authorities.",
+				Attributes: map[string]schema.Attribute{
+					"ca_pem": schema.StringAttribute{
+						MarkdownDescription: "PEM encoded certificate authority bundle used to verify the appliance certificate. Conflicts with `ca_file`. " +
+							"Defaults to the `GUARDIUM_CA_PEM` environment variable when neither `ca_pem` nor `ca_file` is set",
+						Optional: true,
+					},
+					"ca_file": schema.StringAttribute{
+						MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the appliance certificate. Conflicts with `ca_pem`. " +
+							"Defaults to the `GUARDIUM_CA_FILE` environment variable when neither `ca_pem` nor `ca_file` is set",
+						Optional: true,
+					},
+					"server_name": schema.StringAttribute{
+						MarkdownDescription: "Server name expected in the appliance certificate, defaults to `host`",
+						Optional:            true,
+					},
+					"min_version": schema.StringAttribute{
+						MarkdownDescription: "Minimum TLS version, either `1.2` or `1.3`. Defaults to `1.2`",
+						Optional:            true,
+					},
+					"client_cert_pem": schema.StringAttribute{
+						MarkdownDescription: "PEM encoded client certificate presented for mutual TLS. Conflicts with `client_cert_file`",
+						Optional:            true,
+					},
+					"client_cert_file": schema.StringAttribute{
+						MarkdownDescription: "Path to a PEM encoded client certificate presented for mutual TLS. Conflicts with `client_cert_pem`",
+						Optional:            true,
+					},
+					"client_key_pem": schema.StringAttribute{
+						MarkdownDescription: "PEM encoded private key of the client certificate. Conflicts with `client_key_file`",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"client_key_file": schema.StringAttribute{
+						MarkdownDescription: "Path to the PEM encoded private key of the client certificate. Conflicts with `client_key_pem`",
+						Optional:            true,
+					},
+					"insecure_skip_verify": schema.BoolAttribute{
+						MarkdownDescription: "Skip verification of the appliance certificate. Only intended for development appliances with self-signed certificates",
+						Optional:            true,
+					},
+				},
+			},
+			"rate_limit": schema.SingleNestedBlock{
+				MarkdownDescription: "Limits on the load put on the Guardium Data Protection host. The limits apply to every request sent to " +
+					"the host, whatever Terraform `-parallelism` is, and are shared by provider configurations targeting the same host and port. " +
+					"When those set different limits the strictest apply.",
+				Attributes: map[string]schema.Attribute{
+					"max_concurrent_requests": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of requests in flight at once, `0` removes the limit. Defaults to `4`",
+						Optional:            true,
+					},
+					"requests_per_second": schema.Float64Attribute{
+						MarkdownDescription: "Sustained number of requests per second, `0` removes the limit. Defaults to `0`",
+						Optional:            true,
+					},
+					"burst": schema.Int64Attribute{
+						MarkdownDescription: "Number of requests that may be sent at once above `requests_per_second`. Defaults to `1`",
+						Optional:            true,
+					},
+				},
+			},
+			"retry": schema.SingleNestedBlock{
+				MarkdownDescription: "Retry settings for requests failing with transient errors such as HTTP 429, 502 or 503 or a refused connection. " +
+					"Requests that may already have been processed by the appliance are only retried when they are idempotent.",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: "Total number of attempts per request, `1` disables retries. Defaults to `4`",
+						Optional:            true,
+					},
+					"min_backoff": schema.StringAttribute{
+						MarkdownDescription: "Wait before the first retry as a duration such as `500ms` or `1s`, doubled on every attempt. Defaults to `1s`",
+						Optional:            true,
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: "Maximum wait between attempts, also capping `Retry-After` sent by the appliance. Defaults to `30s`",
+						Optional:            true,
+					},
+				},
 			},
 		},
 	}
 }
 
+// ValidateConfig rejects conflicting or malformed settings and warns when certificate verification is disabled
+func (p *GuardiumDataProtectionProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	// Only the credential and TLS attributes are read, the others may not be known yet. Values missing from
+	// the configuration are taken from the environment so that they are validated the way Configure uses them.
+	var settings guardiumDataProtectionModel
+	for i, target := range []*types.String{
+		&settings.GrantType, &settings.ClientID, &settings.ClientSecret, &settings.Username,
+		&settings.Password, &settings.RefreshToken, &settings.ClientAuth,
+		&settings.PasswordFile, &settings.ClientSecretFile, &settings.CredentialProcess,
+	} {
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(credentialAttributes[i]), target)...)
+	}
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tls"), &settings.TLS)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, c := range []struct {
+		value, file types.String
+		attribute   string
+	}{
+		{settings.Password, settings.PasswordFile, "password"},
+		{settings.ClientSecret, settings.ClientSecretFile, "client_secret"},
+	} {
+		if !c.value.IsNull() && !c.file.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(c.attribute+"_file"),
+				"Conflicting provider credentials",
+				fmt.Sprintf("Only one of %q and %q can be set.", c.attribute, c.attribute+"_file"),
+			)
+		}
+	}
+	sources := settings.applyEnvironment(&resp.Diagnostics)
+
+	if c := settings.gdpCredentials(); c != nil && settings.credentialsKnown() {
+		if err := c.Validate(); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid provider credentials",
+				fmt.Sprintf("%s. Credentials were read from: %s.", err, sources.describe(credentialAttributes...)),
+			)
+		}
+	}
+
+	if settings.TLS != nil {
+		validateTLSConfig(settings.TLS, &resp.Diagnostics)
+	}
+
+	var requestTimeout types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("request_timeout"), &requestTimeout)...)
+	if !requestTimeout.IsUnknown() {
+		if _, err := parseRequestTimeout(requestTimeout); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("request_timeout"), "Invalid request timeout", err.Error())
+		}
+	}
+
+	var rateLimitConfig *rateLimitModel
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rate_limit"), &rateLimitConfig)...)
+	if rateLimitConfig != nil && !rateLimitConfig.MaxConcurrentRequests.IsUnknown() && !rateLimitConfig.RequestsPerSecond.IsUnknown() && !rateLimitConfig.Burst.IsUnknown() {
+		if _, err := rateLimitConfig.gdpRateLimitConfig(); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("rate_limit"), "Invalid rate limit configuration", err.Error())
+		}
+	}
+
+	var retryConfig *retryModel
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("retry"), &retryConfig)...)
+	if retryConfig != nil && !retryConfig.MaxAttempts.IsUnknown() && !retryConfig.MinBackoff.IsUnknown() && !retryConfig.MaxBackoff.IsUnknown() {
+		if _, err := retryConfig.gdpRetryConfig(); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("retry"), "Invalid retry configuration", err.Error())
+		}
+	}
+}
+
+// validateTLSConfig rejects conflicting TLS settings and warns when certificate verification is disabled
+func validateTLSConfig(tlsConfig *tlsModel, diags *diag.Diagnostics) {
+	conflicts := []struct {
+		inline, file           string
+		inlineValue, fileValue types.String
+	}{
+		{"ca_pem", "ca_file", tlsConfig.CAPEM, tlsConfig.CAFile},
+		{"client_cert_pem", "client_cert_file", tlsConfig.ClientCertPEM, tlsConfig.ClientCertFile},
+		{"client_key_pem", "client_key_file", tlsConfig.ClientKeyPEM, tlsConfig.ClientKeyFile},
+	}
+	for _, c := range conflicts {
+		if !c.inlineValue.IsNull() && !c.fileValue.IsNull() {
+			diags.AddAttributeError(
+				path.Root("tls").AtName(c.inline),
+				"Conflicting TLS configuration",
+				fmt.Sprintf("Only one of %q and %q can be set.", c.inline, c.file),
+			)
+		}
+	}
+
+	hasCert := !tlsConfig.ClientCertPEM.IsNull() || !tlsConfig.ClientCertFile.IsNull()
+	hasKey := !tlsConfig.ClientKeyPEM.IsNull() || !tlsConfig.ClientKeyFile.IsNull()
+	if hasCert != hasKey {
+		diags.AddAttributeError(
+			path.Root("tls"),
+			"Incomplete mutual TLS configuration",
+			"A client certificate and a client key must be configured together.",
+		)
+	}
+
+	if !tlsConfig.MinVersion.IsNull() && !tlsConfig.MinVersion.IsUnknown() {
+		if _, err := gdp.ParseTLSVersion(tlsConfig.MinVersion.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("tls").AtName("min_version"), "Invalid TLS version", err.Error())
+		}
+	}
+
+	if tlsConfig.InsecureSkipVerify.ValueBool() {
+		diags.AddAttributeWarning(
+			path.Root("tls").AtName("insecure_skip_verify"),
+			"TLS certificate verification is disabled",
+			"The Guardium Data Protection appliance certificate will not be verified, which exposes access tokens "+
+				"and credentials to interception. Configure `ca_pem` or `ca_file` instead for production appliances.",
+		)
+	}
+}
+
 // Configure takes in the defined parameters in the TF module and creates a template gdp client for future use
 func (p *GuardiumDataProtectionProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	tflog.Info(ctx, "configuration provider configuration")
@@ -63,10 +537,119 @@ func (p *GuardiumDataProtectionProvider) Configure(ctx context.Context, req prov
 		return
 	}
 
-	client := gdp.NewClient(data.Host, data.Port)
+	sources := data.applyEnvironment(&resp.Diagnostics)
+	requireSetting(data.Host, "host", envHost, &resp.Diagnostics)
+	requireSetting(data.Port, "port", envPort, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "provider settings resolved", sources.fields())
+
+	client := gdp.NewClient(data.Host.ValueString(), data.Port.ValueString())
+	if err := client.ConfigureTLS(data.TLS.gdpTLSConfig()); err != nil {
+		detail := err.Error()
+		if caSources := sources.describe("tls.ca_file", "tls.ca_pem"); caSources != "" {
+			detail = fmt.Sprintf("%s. The certificate authority was read from: %s.", err, caSources)
+		}
+		resp.Diagnostics.AddAttributeError(path.Root("tls"), "Invalid TLS configuration", detail)
+		return
+	}
+
+	retryConfig, err := data.Retry.gdpRetryConfig()
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("retry"), "Invalid retry configuration", err.Error())
+		return
+	}
+	client.ConfigureRetry(retryConfig)
+
+	rateLimitConfig, err := data.RateLimit.gdpRateLimitConfig()
+	if err == nil {
+		err = client.ConfigureRateLimit(rateLimitConfig)
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("rate_limit"), "Invalid rate limit configuration", err.Error())
+		return
+	}
+
+	requestTimeout, err := parseRequestTimeout(data.RequestTimeout)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("request_timeout"), "Invalid request timeout", err.Error())
+		return
+	}
+	client.ConfigureRequestTimeout(requestTimeout)
+
+	proxyConfig := gdp.ProxyConfig{URL: data.ProxyURL.ValueString(), NoProxy: data.NoProxy.ValueString()}
+	if err := client.ConfigureProxy(proxyConfig); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("proxy_url"), "Invalid proxy configuration", err.Error())
+		return
+	}
+
+	var extraHeaders map[string]string
+	if !data.ExtraHeaders.IsUnknown() {
+		resp.Diagnostics.Append(data.ExtraHeaders.ElementsAs(ctx, &extraHeaders, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if err := client.ConfigureHeaders(extraHeaders); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("extra_headers"), "Invalid extra headers", err.Error())
+		return
+	}
+
+	fixtureConfig := fixtureConfigFromEnvironment()
+	if err := client.ConfigureFixtures(fixtureConfig); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid fixture configuration",
+			fmt.Sprintf("%s. Fixtures are configured with the %s and %s environment variables.", err, envFixturesMode, envFixturesFile),
+		)
+		return
+	}
+	if fixtureConfig.Mode != "" {
+		tflog.Warn(ctx, "Guardium Data Protection requests use fixtures", map[string]any{"mode": fixtureConfig.Mode, "path": fixtureConfig.Path})
+	}
+
+	credentials := data.gdpCredentials()
+	if credentials != nil {
+		client.ConfigureCredentials(*credentials)
+	}
+
+	if unit := data.TargetUnit.ValueString(); unit != "" {
+		client.ConfigureTargetUnit(unit)
+	}
+
+	// Resources and data sources share the API verifying the appliance certificate with the provider `tls` settings
+	api, err := client.NewSecureClient("")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("tls"), "Invalid TLS configuration", err.Error())
+		return
+	}
+
+	// Nothing is requested from the appliance unless the preflight check is enabled, access tokens are obtained
+	// when the first resource calls it. The preflight check obtains the token itself, telling connection problems
+	// apart from invalid credentials, then verifies the target unit, which resources verify otherwise.
+	if data.Preflight.ValueBool() && data.credentialsKnown() {
+		preflight(ctx, api, sources, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if unit := data.TargetUnit.ValueString(); unit != "" && api.HasCredentials() {
+			err := api.ValidateTargetUnit(ctx, "", unit)
+			// The unit is only at fault when the managed units could be listed
+			var apiErr *gdp.APIError
+			if gdp.IsCanceled(err) || errors.As(err, &apiErr) {
+				addClientError(&resp.Diagnostics, "Failed to verify target unit", err.Error(), err)
+				return
+			}
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("target_unit"), "Invalid target unit", err.Error())
+				return
+			}
+		}
+	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.DataSourceData = api
+	resp.ResourceData = api
 	tflog.Info(ctx, "provider configuration configured")
 }
 
@@ -78,12 +661,16 @@ func (p *GuardiumDataProtectionProvider) Resources(ctx context.Context) []func()
 		NewConfigureVADatasourceResource,
 		NewConfigureVANotificationsResource,
 		NewAWSSecretsManagerResource,
+		NewRestCommandResource,
 	}
 }
 
 func (p *GuardiumDataProtectionProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewAuthenticationDataSource,
+		NewRestCallDataSource,
+		NewApplianceVersionDataSource,
+		NewHealthDataSource,
 	}
 }
 
@@ -0,0 +1,90 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+// testAccProtoV6ProviderFactories serve the provider to Terraform in acceptance tests
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"guardium-data-protection": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccAppliance is a mock appliance serving one acceptance test
+type testAccAppliance struct {
+	*gdptest.Server
+	// Host and Port are the address the server listens on
+	Host string
+	Port string
+	// CAFile is the PEM encoded certificate of the server, for ca_path and the provider tls block
+	CAFile string
+	// ProviderConfig is the provider block pointing at the server with its default credentials
+	ProviderConfig string
+}
+
+// newTestAccAppliance starts a mock appliance for the duration of t. Acceptance tests are skipped unless
+// TF_ACC is set, like every test calling resource.Test.
+func newTestAccAppliance(t *testing.T) *testAccAppliance {
+	t.Helper()
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	server := gdptest.NewServer()
+	ts := httptest.NewTLSServer(server)
+	t.Cleanup(ts.Close)
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testAccAppliance{
+		Server: server,
+		Host:   host,
+		Port:   port,
+		CAFile: caFile,
+		ProviderConfig: fmt.Sprintf(`
+provider "guardium-data-protection" {
+  host          = %q
+  port          = %q
+  client_id     = %q
+  client_secret = %q
+  username      = %q
+  password      = %q
+
+  tls {
+    ca_file = %q
+  }
+}
+`, host, port, server.ClientID, server.ClientSecret, server.Username, server.Password, caFile),
+	}
+}
+
+// config returns the provider block followed by the resources and data sources in config
+func (a *testAccAppliance) config(config string, args ...any) string {
+	return a.ProviderConfig + fmt.Sprintf(config, args...)
+}
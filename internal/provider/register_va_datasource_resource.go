@@ -6,11 +6,12 @@ package provider
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -35,16 +36,18 @@ func NewRegisterVADatasourceResource() resource.Resource {
 
 // registerVADatasourceResource is the resource implementation.
 type registerVADatasourceResource struct {
-	client *gdp.Client
+	client gdp.API
 }
 
 // registerVADatasourceResourceModel maps the resource schema data.
 type registerVADatasourceResourceModel struct {
-	ID                 types.String `tfsdk:"id"`
-	AccessToken        types.String `tfsdk:"access_token"`
-	Payload            types.String `tfsdk:"payload"`
-	CAPath             types.String `tfsdk:"ca_path"`
-	LastRegisteredTime types.String `tfsdk:"last_registered_time"`
+	ID                 types.String   `tfsdk:"id"`
+	AccessToken        types.String   `tfsdk:"access_token"`
+	Payload            types.String   `tfsdk:"payload"`
+	CAPath             types.String   `tfsdk:"ca_path"`
+	LastRegisteredTime types.String   `tfsdk:"last_registered_time"`
+	TargetUnit         types.String   `tfsdk:"target_unit"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *registerVADatasourceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -63,12 +66,12 @@ func (r *registerVADatasourceResource) Schema(ctx context.Context, req resource.
 				Sensitive:           true,
 			},
 			"access_token": schema.StringAttribute{
-				MarkdownDescription: "Access token for authentication",
-				Required:            true,
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
 				Sensitive:           true,
 			},
 			"ca_path": schema.StringAttribute{
-				MarkdownDescription: "Guardium Data Protection certificate authority",
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
 				Optional:            true,
 			},
 			"last_registered_time": schema.StringAttribute{
@@ -82,6 +85,10 @@ func (r *registerVADatasourceResource) Schema(ctx context.Context, req resource.
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"target_unit": targetUnitAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -92,11 +99,11 @@ func (r *registerVADatasourceResource) Configure(ctx context.Context, req resour
 		return
 	}
 
-	client, ok := req.ProviderData.(*gdp.Client)
+	client, ok := req.ProviderData.(gdp.API)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *gdp.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected gdp.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -113,6 +120,13 @@ func (r *registerVADatasourceResource) Create(ctx context.Context, req resource.
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
 	var (
 		payload = data.Payload.ValueString()
 		err     error
@@ -121,7 +135,6 @@ func (r *registerVADatasourceResource) Create(ctx context.Context, req resource.
 	if payload[0] == '"' {
 		payload, err = strconv.Unquote(data.Payload.ValueString())
 		if err != nil {
-			tflog.Info(ctx, fmt.Sprintf("payload %s", data.Payload.ValueString()))
 			resp.Diagnostics.AddError(
 				"Failed to unquote payload",
 				fmt.Sprintf("Failed to unquote payload: %s.", err.Error()),
@@ -130,25 +143,31 @@ func (r *registerVADatasourceResource) Create(ctx context.Context, req resource.
 		}
 	}
 
-	if data.CAPath.IsNull() {
-		err := r.client.NewInsecureClient().RegisterVADataSource(ctx, data.AccessToken.ValueString(), []byte(payload))
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to register va",
-				fmt.Sprintf("Failed to register va: %s.", err.Error()),
-			)
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	err = c.RegisterVADataSource(ctx, data.AccessToken.ValueString(), []byte(payload))
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Failed to register va",
+			fmt.Sprintf("Failed to register va: %s.", err.Error()),
+			err,
+		)
+		return
 	}
 
 	// Set computed values
 	currentTime := time.Now().Format(time.RFC3339)
 	data.LastRegisteredTime = types.StringValue(currentTime)
 
-	// Set ID based on datasource name and host
-	hash := sha256.New()
-
-	data.ID = types.StringValue(fmt.Sprintf("%s-%s", hash.Sum([]byte(data.Payload.ValueString())), currentTime))
+	data.ID = types.StringValue(registeredDatasourceID(payload))
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -180,6 +199,13 @@ func (r *registerVADatasourceResource) Update(ctx context.Context, req resource.
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
 	var (
 		payload = data.Payload.ValueString()
 		err     error
@@ -188,7 +214,6 @@ func (r *registerVADatasourceResource) Update(ctx context.Context, req resource.
 	if payload[0] == '"' {
 		payload, err = strconv.Unquote(data.Payload.ValueString())
 		if err != nil {
-			tflog.Info(ctx, fmt.Sprintf("payload %s", data.Payload.ValueString()))
 			resp.Diagnostics.AddError(
 				"Failed to unquote payload",
 				fmt.Sprintf("Failed to unquote payload: %s.", err.Error()),
@@ -197,15 +222,24 @@ func (r *registerVADatasourceResource) Update(ctx context.Context, req resource.
 		}
 	}
 
-	if data.CAPath.IsNull() {
-		err := r.client.NewInsecureClient().RegisterVADataSource(ctx, data.AccessToken.ValueString(), []byte(payload))
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to register va",
-				fmt.Sprintf("Failed to register va: %s.", err.Error()),
-			)
-			return
-		}
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	err = c.RegisterVADataSource(ctx, data.AccessToken.ValueString(), []byte(payload))
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Failed to register va",
+			fmt.Sprintf("Failed to register va: %s.", err.Error()),
+			err,
+		)
+		return
 	}
 
 	// Set computed values
@@ -232,6 +266,33 @@ func (r *registerVADatasourceResource) Delete(ctx context.Context, req resource.
 	// No action needed on delete - the resource will be removed from state
 }
 
+// registeredDatasourceID returns the ID of the registration of payload, the name of the datasource as
+// registering it again updates it in place. Payloads without a name are identified by their hash.
+func registeredDatasourceID(payload string) string {
+	var registration struct {
+		DatasourceName string `json:"datasourceName"`
+	}
+	if err := json.Unmarshal([]byte(payload), &registration); err == nil && registration.DatasourceName != "" {
+		return registration.DatasourceName
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(payload)))
+}
+
+// ImportState imports a registration by its payload, which the appliance does not report
 func (r *registerVADatasourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Payloads may be given as a quoted JSON document, as when configured
+	payload := req.ID
+	if unquoted, err := strconv.Unquote(payload); err == nil {
+		payload = unquoted
+	}
+	if !json.Valid([]byte(payload)) {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"The appliance does not report datasource registrations, import them with their payload as a JSON document.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), registeredDatasourceID(payload))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("payload"), req.ID)...)
 }
@@ -0,0 +1,130 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+func TestRegisterVADatasourceResourceCreate(t *testing.T) {
+	payload := `{"datasourceName":"db2-prod","datasourceType":"DB2"}`
+
+	testCases := []struct {
+		name       string
+		payload    string
+		expectedID string
+	}{
+		{name: "Payload", payload: payload, expectedID: "db2-prod"},
+		{name: "Quoted payload", payload: strconv.Quote(payload), expectedID: "db2-prod"},
+		{name: "Payload without name", payload: `{"datasourceType":"DB2"}`, expectedID: "b76f7b77a18dbcdb44533a6f804f5c50b6dd49d511fdbe92e66bcd07197d32ea"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := gdptest.NewFake()
+			model := registerVADatasourceResourceModel{
+				ID:                 types.StringUnknown(),
+				AccessToken:        types.StringNull(),
+				Payload:            types.StringValue(tc.payload),
+				CAPath:             types.StringNull(),
+				LastRegisteredTime: types.StringUnknown(),
+				TargetUnit:         types.StringNull(),
+				Timeouts:           nullTimeouts(),
+			}
+			resp := createResource(t, NewRegisterVADatasourceResource(), fake, &model)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected create diagnostics: %v", resp.Diagnostics)
+			}
+
+			if len(fake.VADataSources) != 1 || !json.Valid(fake.VADataSources[0]) {
+				t.Errorf("Expected the datasource to be registered with a JSON document, got %s", fake.VADataSources)
+			}
+			var created registerVADatasourceResourceModel
+			resp.State.Get(context.Background(), &created)
+			if created.ID.ValueString() != tc.expectedID {
+				t.Errorf("Expected ID %s, got %s", tc.expectedID, created.ID)
+			}
+
+			// The registration is imported with its payload as configured
+			importResp := importResource(t, NewRegisterVADatasourceResource(), fake, tc.payload)
+			var imported registerVADatasourceResourceModel
+			importResp.State.Get(context.Background(), &imported)
+			if importResp.Diagnostics.HasError() || imported.ID != created.ID || imported.Payload != created.Payload {
+				t.Errorf("Expected the import to match the created state, got %+v and %v", imported, importResp.Diagnostics)
+			}
+		})
+	}
+
+	resp := importResource(t, NewRegisterVADatasourceResource(), gdptest.NewFake(), "db2-prod")
+	if !resp.Diagnostics.HasError() {
+		t.Error("Expected an import ID that is not a JSON document to be rejected")
+	}
+}
+
+func testAccRegisterVADatasourceConfig(appliance *testAccAppliance, host string) string {
+	return appliance.config(`
+resource "guardium-data-protection_register_va_datasource" "test" {
+  payload = jsonencode({
+    datasourceName = "db2-prod"
+    datasourceType = "DB2"
+    host           = %q
+    port           = 50000
+  })
+  ca_path = %q
+}
+`, host, appliance.CAFile)
+}
+
+// testAccCheckDataSourceHost checks the host the mock appliance registered the db2-prod datasource with
+func testAccCheckDataSourceHost(appliance *testAccAppliance, host string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		var registered struct {
+			Host string `json:"host"`
+		}
+		if err := json.Unmarshal(appliance.DataSource("db2-prod"), &registered); err != nil || registered.Host != host {
+			return fmt.Errorf("expected db2-prod to be registered on %s, got %s", host, appliance.DataSource("db2-prod"))
+		}
+		return nil
+	}
+}
+
+func TestAccRegisterVADatasourceResource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRegisterVADatasourceConfig(appliance, "db.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("guardium-data-protection_register_va_datasource.test", "id", "db2-prod"),
+					resource.TestCheckResourceAttrSet("guardium-data-protection_register_va_datasource.test", "last_registered_time"),
+					testAccCheckDataSourceHost(appliance, "db.example.com"),
+				),
+			},
+			{
+				Config: testAccRegisterVADatasourceConfig(appliance, "db2.example.com"),
+				Check:  testAccCheckDataSourceHost(appliance, "db2.example.com"),
+			},
+			{
+				ResourceName:      "guardium-data-protection_register_va_datasource.test",
+				ImportState:       true,
+				ImportStateId:     `{"datasourceName":"db2-prod","datasourceType":"DB2","host":"db2.example.com","port":50000}`,
+				ImportStateVerify: true,
+				// The certificate authority only applies to requests and the time is that of the last apply
+				ImportStateVerifyIgnore: []string{"ca_path", "last_registered_time"},
+			},
+		},
+	})
+}
@@ -0,0 +1,270 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource                   = &restCallDataSource{}
+	_ datasource.DataSourceWithConfigure      = &restCallDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &restCallDataSource{}
+)
+
+// NewRestCallDataSource is a helper function to simplify the provider implementation.
+func NewRestCallDataSource() datasource.DataSource {
+	return &restCallDataSource{}
+}
+
+// restCallDataSource reads the result of an arbitrary GuardAPI command
+type restCallDataSource struct {
+	client gdp.API
+}
+
+// restCallDataSourceModel maps the data source schema data.
+type restCallDataSourceModel struct {
+	Command      types.String  `tfsdk:"command"`
+	Parameters   types.Map     `tfsdk:"parameters"`
+	AccessToken  types.String  `tfsdk:"access_token"`
+	CAPath       types.String  `tfsdk:"ca_path"`
+	TargetUnit   types.String  `tfsdk:"target_unit"`
+	Pagination   types.Object  `tfsdk:"pagination"`
+	StatusCode   types.Int64   `tfsdk:"status_code"`
+	ResponseBody types.String  `tfsdk:"response_body"`
+	Result       types.Dynamic `tfsdk:"result"`
+}
+
+// paginationModel maps the pagination attribute of the rest_call data source
+type paginationModel struct {
+	PageSize types.Int64 `tfsdk:"page_size"`
+	Limit    types.Int64 `tfsdk:"limit"`
+}
+
+// paginationFromObject converts the pagination attribute into a paginationModel, returning nil when it is not
+// configured or not known yet
+func paginationFromObject(ctx context.Context, obj types.Object) (*paginationModel, diag.Diagnostics) {
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil, nil
+	}
+
+	var m paginationModel
+	diags := obj.As(ctx, &m, basetypes.ObjectAsOptions{})
+	return &m, diags
+}
+
+// pageOptions converts the model to gdp.PageOptions
+func (m *paginationModel) pageOptions() gdp.PageOptions {
+	return gdp.PageOptions{PageSize: int(m.PageSize.ValueInt64()), Limit: int(m.Limit.ValueInt64())}
+}
+
+func (d *restCallDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rest_call"
+}
+
+func (d *restCallDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Runs a read-only GuardAPI command with a `GET` request to `/restAPI/<command>` and returns the decoded response. Use it to read Guardium Data Protection objects the provider has no dedicated data source for",
+
+		Attributes: map[string]schema.Attribute{
+			"command": schema.StringAttribute{
+				MarkdownDescription: "GuardAPI command to run, e.g. `datasource` or `va/config`",
+				Required:            true,
+			},
+			"parameters": schema.MapAttribute{
+				MarkdownDescription: "Command parameters, sent as query parameters",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"ca_path": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
+				Optional:            true,
+			},
+			"target_unit": schema.StringAttribute{
+				MarkdownDescription: targetUnitDescription,
+				Optional:            true,
+			},
+			"pagination": schema.SingleNestedAttribute{
+				MarkdownDescription: "Reads a list command page by page, with the `offset` and `limit` query parameters, until every item or `limit` items were read. " +
+					"`result` is then the list of items and `response_body` its JSON encoding. Appliances that ignore or reject the page parameters return the whole list at once",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"page_size": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("Number of items requested per page. Defaults to `%d`", gdp.DefaultPageSize),
+						Optional:            true,
+					},
+					"limit": schema.Int64Attribute{
+						MarkdownDescription: "Largest number of items read. Defaults to every item",
+						Optional:            true,
+					},
+				},
+			},
+			"status_code": schema.Int64Attribute{
+				MarkdownDescription: "HTTP status returned by the appliance, for the last page with `pagination`",
+				Computed:            true,
+			},
+			"response_body": schema.StringAttribute{
+				MarkdownDescription: "Raw response body",
+				Computed:            true,
+			},
+			"result": schema.DynamicAttribute{
+				MarkdownDescription: "Response body decoded from JSON, null when the response is not JSON",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *restCallDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(gdp.API)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected gdp.API, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *restCallDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data restCallDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.command().validate(path.Empty(), http.MethodGet, &resp.Diagnostics)
+
+	pagination, diags := paginationFromObject(ctx, data.Pagination)
+	resp.Diagnostics.Append(diags...)
+	if pagination != nil {
+		for name, value := range map[string]types.Int64{"page_size": pagination.PageSize, "limit": pagination.Limit} {
+			if !value.IsNull() && !value.IsUnknown() && value.ValueInt64() < 1 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("pagination").AtName(name),
+					"Invalid pagination",
+					fmt.Sprintf("`%s` must be at least 1, got %d.", name, value.ValueInt64()),
+				)
+			}
+		}
+	}
+}
+
+// command returns the GuardAPI command described by the data source
+func (m restCallDataSourceModel) command() restCommandModel {
+	return restCommandModel{
+		Method:     types.StringNull(),
+		Command:    m.Command,
+		Parameters: m.Parameters,
+		Body:       types.StringNull(),
+	}
+}
+
+func (d *restCallDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data restCallDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cmd, diags := data.command().gdpCommand(ctx, http.MethodGet)
+	resp.Diagnostics.Append(diags...)
+	pagination, diags := paginationFromObject(ctx, data.Pagination)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+	c, err := newGDPAPI(d.client, data.CAPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return
+	}
+
+	var result *gdp.Response
+	if pagination != nil {
+		result, err = listAll(ctx, c, data.AccessToken.ValueString(), cmd, pagination.pageOptions())
+	} else {
+		result, err = c.Execute(ctx, data.AccessToken.ValueString(), cmd)
+	}
+	if err != nil {
+		addClientError(
+			&resp.Diagnostics,
+			"Failed to run GuardAPI command",
+			fmt.Sprintf("Failed to run %s: %s.", cmd.Name, err.Error()),
+			err,
+		)
+		return
+	}
+
+	restResult := newRestCommandResult(result)
+	data.StatusCode = restResult.StatusCode
+	data.ResponseBody = restResult.ResponseBody
+	data.Result = restResult.Result
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// statusRecorder records the status of the last response to a command run through it
+type statusRecorder struct {
+	gdp.Executor
+	statusCode int
+}
+
+func (r *statusRecorder) Execute(ctx context.Context, accessToken string, cmd gdp.Command) (*gdp.Response, error) {
+	resp, err := r.Executor.Execute(ctx, accessToken, cmd)
+	if err == nil {
+		r.statusCode = resp.StatusCode
+	}
+	return resp, err
+}
+
+// listAll reads every page of the list command cmd and returns the items as a single response, with the
+// status of the last page
+func listAll(ctx context.Context, e gdp.Executor, accessToken string, cmd gdp.Command, opts gdp.PageOptions) (*gdp.Response, error) {
+	recorder := &statusRecorder{Executor: e}
+	items := []json.RawMessage{}
+	for item, err := range gdp.List[json.RawMessage](ctx, recorder, accessToken, cmd, opts) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	body, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	return &gdp.Response{StatusCode: recorder.statusCode, Header: http.Header{}, Body: body}, nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRestCallDataSource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The data source reads back what the resource configured
+				Config: testAccConfigureVADatasourceConfig(appliance, "Monday") + `
+data "guardium-data-protection_rest_call" "test" {
+  command    = "va/config"
+  parameters = { datasourceName = guardium-data-protection_configure_va_datasource.test.datasource_name }
+  ca_path    = guardium-data-protection_configure_va_datasource.test.ca_path
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.guardium-data-protection_rest_call.test", "status_code", "200"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_rest_call.test", "result.schedule.day", "Monday"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRestCallPaginationConfig(appliance *testAccAppliance, pagination string) string {
+	return appliance.config(`
+resource "guardium-data-protection_register_va_datasource" "test" {
+  count   = 5
+  payload = jsonencode({ datasourceName = "db2-${count.index}", datasourceType = "DB2" })
+  ca_path = %q
+}
+
+data "guardium-data-protection_rest_call" "test" {
+  command    = "datasource"
+  pagination = %s
+  ca_path    = guardium-data-protection_register_va_datasource.test[4].ca_path
+  depends_on = [guardium-data-protection_register_va_datasource.test]
+}
+`, appliance.CAFile, pagination)
+}
+
+func TestAccRestCallDataSourcePagination(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRestCallPaginationConfig(appliance, "{ page_size = 0 }"),
+				ExpectError: regexp.MustCompile("`page_size` must be at least 1"),
+			},
+			{
+				// Pagination that is not known until the datasources are registered is validated when the data source is read
+				Config: testAccRestCallPaginationConfig(appliance, `guardium-data-protection_register_va_datasource.test[0].id != "" ? { page_size = 2 } : null`),
+				Check:  resource.TestCheckResourceAttr("data.guardium-data-protection_rest_call.test", "result.#", "5"),
+			},
+			{
+				Config: testAccRestCallPaginationConfig(appliance, "{ page_size = 2 }"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.guardium-data-protection_rest_call.test", "result.#", "5"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_rest_call.test", "result.4.datasourceName", "db2-4"),
+				),
+			},
+			{
+				Config: testAccRestCallPaginationConfig(appliance, "{ page_size = 2, limit = 3 }"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.guardium-data-protection_rest_call.test", "result.#", "3"),
+					resource.TestCheckResourceAttr("data.guardium-data-protection_rest_call.test", "status_code", "200"),
+				),
+			},
+		},
+	})
+}
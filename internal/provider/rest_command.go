@@ -0,0 +1,172 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// restMethods are the HTTP methods accepted by the rest_call data source and rest_command resource
+var restMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// restCommandModel describes a GuardAPI command in the rest_call data source and rest_command resource
+type restCommandModel struct {
+	Method     types.String `tfsdk:"method"`
+	Command    types.String `tfsdk:"command"`
+	Parameters types.Map    `tfsdk:"parameters"`
+	Body       types.String `tfsdk:"body"`
+}
+
+// method returns the configured HTTP method, or defaultMethod when it is not set
+func (m restCommandModel) method(defaultMethod string) string {
+	if m.Method.IsNull() || m.Method.ValueString() == "" {
+		return defaultMethod
+	}
+	return strings.ToUpper(m.Method.ValueString())
+}
+
+// validate reports an unsupported method, conflicting parameters and a body that is not JSON. Unknown
+// values are skipped, they are validated again once known.
+func (m restCommandModel) validate(at path.Path, defaultMethod string, diags *diag.Diagnostics) {
+	if !m.Method.IsUnknown() && !slices.Contains(restMethods, m.method(defaultMethod)) {
+		diags.AddAttributeError(
+			at.AtName("method"),
+			"Invalid method",
+			fmt.Sprintf("Method must be one of %s, got %q.", strings.Join(restMethods, ", "), m.Method.ValueString()),
+		)
+	}
+
+	if !m.Command.IsUnknown() && strings.Trim(m.Command.ValueString(), "/ ") == "" {
+		diags.AddAttributeError(at.AtName("command"), "Invalid command", "The GuardAPI command must not be empty.")
+	}
+
+	if !m.Parameters.IsNull() && !m.Body.IsNull() {
+		diags.AddAttributeError(
+			at.AtName("body"),
+			"Conflicting command parameters",
+			"Only one of `parameters` and `body` can be set.",
+		)
+	}
+
+	if !m.Body.IsNull() && !m.Body.IsUnknown() && !json.Valid([]byte(m.Body.ValueString())) {
+		diags.AddAttributeError(at.AtName("body"), "Invalid command body", "The body must be a JSON document.")
+	}
+
+	if !m.Body.IsNull() && !m.Method.IsUnknown() && m.method(defaultMethod) == http.MethodGet {
+		diags.AddAttributeError(at.AtName("body"), "Invalid command body", "GET commands cannot send a body, use `parameters` instead.")
+	}
+}
+
+// gdpCommand converts the model to a gdp.Command
+func (m restCommandModel) gdpCommand(ctx context.Context, defaultMethod string) (gdp.Command, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	cmd := gdp.Command{
+		Method: m.method(defaultMethod),
+		Name:   m.Command.ValueString(),
+	}
+
+	switch {
+	case !m.Body.IsNull():
+		cmd.Params = json.RawMessage(m.Body.ValueString())
+	case !m.Parameters.IsNull():
+		params := map[string]string{}
+		diags.Append(m.Parameters.ElementsAs(ctx, &params, false)...)
+		cmd.Params = params
+	}
+
+	return cmd, diags
+}
+
+// restCommandResult holds the outcome of a command as stored in state
+type restCommandResult struct {
+	StatusCode   types.Int64
+	ResponseBody types.String
+	Result       types.Dynamic
+}
+
+// newRestCommandResult converts a GuardAPI response into state values. Responses that are not JSON
+// leave result null, their text remains available in response_body.
+func newRestCommandResult(resp *gdp.Response) restCommandResult {
+	return restCommandResult{
+		StatusCode:   types.Int64Value(int64(resp.StatusCode)),
+		ResponseBody: types.StringValue(string(resp.Body)),
+		Result:       jsonToDynamic(resp.Body),
+	}
+}
+
+// jsonToDynamic decodes a JSON document into a dynamic value, or a null value when data is not JSON
+func jsonToDynamic(data []byte) types.Dynamic {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return types.DynamicNull()
+	}
+
+	return types.DynamicValue(jsonToValue(value))
+}
+
+// jsonToValue converts a decoded JSON value into a Terraform value. Objects become objects and arrays
+// become tuples so that elements of different types can be represented.
+func jsonToValue(value any) attr.Value {
+	switch v := value.(type) {
+	case map[string]any:
+		attrTypes := make(map[string]attr.Type, len(v))
+		attrs := make(map[string]attr.Value, len(v))
+		for key, item := range v {
+			attrs[key] = jsonToValue(item)
+			attrTypes[key] = attrs[key].Type(context.Background())
+		}
+		return types.ObjectValueMust(attrTypes, attrs)
+	case []any:
+		elemTypes := make([]attr.Type, len(v))
+		elems := make([]attr.Value, len(v))
+		for i, item := range v {
+			elems[i] = jsonToValue(item)
+			elemTypes[i] = elems[i].Type(context.Background())
+		}
+		return types.TupleValueMust(elemTypes, elems)
+	case string:
+		return types.StringValue(v)
+	case bool:
+		return types.BoolValue(v)
+	case json.Number:
+		number, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return types.StringValue(v.String())
+		}
+		return types.NumberValue(number)
+	default:
+		return types.StringNull()
+	}
+}
+
+// restCommandFromObject converts an object attribute into a restCommandModel, returning nil when the
+// command is not configured or not known yet
+func restCommandFromObject(ctx context.Context, obj types.Object) (*restCommandModel, diag.Diagnostics) {
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil, nil
+	}
+
+	var m restCommandModel
+	diags := obj.As(ctx, &m, basetypes.ObjectAsOptions{})
+	return &m, diags
+}
@@ -0,0 +1,355 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &restCommandResource{}
+	_ resource.ResourceWithConfigure      = &restCommandResource{}
+	_ resource.ResourceWithValidateConfig = &restCommandResource{}
+)
+
+// NewRestCommandResource is a helper function to simplify the provider implementation.
+func NewRestCommandResource() resource.Resource {
+	return &restCommandResource{}
+}
+
+// restCommandResource runs arbitrary GuardAPI commands when it is created, updated and destroyed
+type restCommandResource struct {
+	client gdp.API
+}
+
+// restCommandResourceModel maps the resource schema data.
+type restCommandResourceModel struct {
+	ID           types.String   `tfsdk:"id"`
+	Create       types.Object   `tfsdk:"create"`
+	Update       types.Object   `tfsdk:"update"`
+	Delete       types.Object   `tfsdk:"delete"`
+	AccessToken  types.String   `tfsdk:"access_token"`
+	CAPath       types.String   `tfsdk:"ca_path"`
+	StatusCode   types.Int64    `tfsdk:"status_code"`
+	ResponseBody types.String   `tfsdk:"response_body"`
+	Result       types.Dynamic  `tfsdk:"result"`
+	TargetUnit   types.String   `tfsdk:"target_unit"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *restCommandResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rest_command"
+}
+
+// restCommandAttribute returns the schema of a command definition using defaultMethod when no method is set
+func restCommandAttribute(description, defaultMethod string, required bool, planModifiers ...planmodifier.Object) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: description,
+		Required:            required,
+		Optional:            !required,
+		PlanModifiers:       planModifiers,
+		Attributes: map[string]schema.Attribute{
+			"method": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("HTTP method: `GET`, `POST`, `PUT`, `PATCH` or `DELETE`. Defaults to `%s`", defaultMethod),
+				Optional:            true,
+			},
+			"command": schema.StringAttribute{
+				MarkdownDescription: "GuardAPI command to run, e.g. `datasource` or `va/config`",
+				Required:            true,
+			},
+			"parameters": schema.MapAttribute{
+				MarkdownDescription: "Command parameters, sent as query parameters for `GET` and as a JSON object otherwise. Conflicts with `body`",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"body": schema.StringAttribute{
+				MarkdownDescription: "JSON request body, e.g. built with `jsonencode`. Conflicts with `parameters` and cannot be sent with `GET`",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *restCommandResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Runs GuardAPI commands against `/restAPI/<command>` when the resource is created, updated and destroyed. Use it to manage Guardium Data Protection objects the provider has no dedicated resource for",
+
+		Attributes: map[string]schema.Attribute{
+			"create": restCommandAttribute(
+				"Command run when the resource is created",
+				http.MethodPost,
+				true,
+				objectplanmodifier.RequiresReplaceIf(
+					requiresReplaceWithoutUpdate,
+					"Changing the create command replaces the resource when no update command is set",
+					"Changing the create command replaces the resource when no `update` command is set",
+				),
+			),
+			"update": restCommandAttribute(
+				"Command run when any argument changes. When it is not set, changing the `create` command replaces the resource",
+				http.MethodPut,
+				false,
+			),
+			"delete": restCommandAttribute(
+				"Command run when the resource is destroyed. When it is not set, the resource is only removed from the state",
+				http.MethodDelete,
+				false,
+			),
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "Access token for authentication. Defaults to a token obtained with the provider credentials",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"ca_path": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM encoded certificate authority bundle used to verify the Guardium Data Protection server certificate. Overrides the provider `tls` certificate authority",
+				Optional:            true,
+			},
+			"status_code": schema.Int64Attribute{
+				MarkdownDescription: "HTTP status returned by the most recent create or update command",
+				Computed:            true,
+			},
+			"response_body": schema.StringAttribute{
+				MarkdownDescription: "Raw response body of the most recent create or update command",
+				Computed:            true,
+			},
+			"result": schema.DynamicAttribute{
+				MarkdownDescription: "Response body of the most recent create or update command decoded from JSON, null when the response is not JSON",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the resource, the `ID` reported by the create command or the command name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"target_unit": targetUnitAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+// requiresReplaceWithoutUpdate replaces the resource when its create command changes and there is no update
+// command to apply the change in place
+func requiresReplaceWithoutUpdate(ctx context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+	var update types.Object
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("update"), &update)...)
+	resp.RequiresReplace = update.IsNull()
+}
+
+func (r *restCommandResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(gdp.API)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected gdp.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *restCommandResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	for name, defaultMethod := range map[string]string{"create": http.MethodPost, "update": http.MethodPut, "delete": http.MethodDelete} {
+		var obj types.Object
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &obj)...)
+
+		command, diags := restCommandFromObject(ctx, obj)
+		resp.Diagnostics.Append(diags...)
+		if command != nil {
+			command.validate(path.Root(name), defaultMethod, &resp.Diagnostics)
+		}
+	}
+}
+
+// run executes the command held in obj and stores its response in data
+func (r *restCommandResource) run(ctx context.Context, data *restCommandResourceModel, obj types.Object, defaultMethod string, diags *diag.Diagnostics) *gdp.Response {
+	command, d := restCommandFromObject(ctx, obj)
+	diags.Append(d...)
+	if diags.HasError() || command == nil {
+		return nil
+	}
+	ctx = withTargetUnit(ctx, data.TargetUnit)
+
+	cmd, d := command.gdpCommand(ctx, defaultMethod)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil
+	}
+
+	c, err := newGDPAPI(r.client, data.CAPath)
+	if err != nil {
+		diags.AddError(
+			"Invalid certificate authority",
+			fmt.Sprintf("Could not load certificate authority: %s.", err.Error()),
+		)
+		return nil
+	}
+
+	result, err := c.Execute(ctx, data.AccessToken.ValueString(), cmd)
+	if err != nil {
+		addClientError(
+			diags,
+			"Failed to run GuardAPI command",
+			fmt.Sprintf("Failed to run %s %s: %s.", cmd.Method, cmd.Name, err.Error()),
+			err,
+		)
+		return nil
+	}
+
+	return result
+}
+
+// setResult stores the outcome of a create or update command in data
+func (m *restCommandResourceModel) setResult(result *gdp.Response) {
+	restResult := newRestCommandResult(result)
+	m.StatusCode = restResult.StatusCode
+	m.ResponseBody = restResult.ResponseBody
+	m.Result = restResult.Result
+}
+
+// responseID returns the ID reported in a GuardAPI response, if any
+func responseID(body []byte) string {
+	var envelope struct {
+		ID any `json:"ID"`
+	}
+	// Numbers are kept as sent, IDs such as 20000000000000001 do not fit a float64
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&envelope); err != nil || envelope.ID == nil {
+		return ""
+	}
+
+	id := fmt.Sprint(envelope.ID)
+	if id == "0" {
+		// Guardium reports ID 0 for commands that do not create an object
+		return ""
+	}
+	return id
+}
+
+func (r *restCommandResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data restCommandResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	result := r.run(ctx, &data, data.Create, http.MethodPost, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.setResult(result)
+
+	id := responseID(result.Body)
+	if id == "" {
+		command, diags := restCommandFromObject(ctx, data.Create)
+		resp.Diagnostics.Append(diags...)
+		if command != nil {
+			id = command.Command.ValueString()
+		}
+	}
+	data.ID = types.StringValue(id)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *restCommandResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data restCommandResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The commands are opaque to the provider so there is nothing to refresh, the state is kept as is
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *restCommandResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state restCommandResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without an update command only arguments that do not require a call changed, such as the delete command
+	data.StatusCode, data.ResponseBody, data.Result = state.StatusCode, state.ResponseBody, state.Result
+	if !data.Update.IsNull() {
+		result := r.run(ctx, &data, data.Update, http.MethodPut, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.setResult(result)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *restCommandResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data restCommandResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without a delete command the resource is only removed from state
+	r.run(ctx, &data, data.Delete, http.MethodDelete, &resp.Diagnostics)
+}
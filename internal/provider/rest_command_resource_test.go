@@ -0,0 +1,177 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+func TestRestCommandResourceCreate(t *testing.T) {
+	ctx := context.Background()
+	fake := gdptest.NewFake()
+	fake.ExecuteFunc = func(ctx context.Context, cmd gdp.Command) (*gdp.Response, error) {
+		return &gdp.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: []byte(`{"ID":42,"Message":"OK"}`)}, nil
+	}
+
+	r := NewRestCommandResource()
+	emptyState := configuredResource(t, r, fake)
+	commandType := emptyState.Schema.(schema.Schema).Attributes["create"].(schema.SingleNestedAttribute).GetType().(types.ObjectType)
+	create, diags := types.ObjectValueFrom(ctx, commandType.AttrTypes, testRestCommand("", "group", `{"name":"servers"}`, nil))
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+
+	model := restCommandResourceModel{
+		ID:           types.StringUnknown(),
+		Create:       create,
+		Update:       types.ObjectNull(commandType.AttrTypes),
+		Delete:       types.ObjectNull(commandType.AttrTypes),
+		AccessToken:  types.StringNull(),
+		CAPath:       types.StringNull(),
+		StatusCode:   types.Int64Unknown(),
+		ResponseBody: types.StringUnknown(),
+		Result:       types.DynamicUnknown(),
+		TargetUnit:   types.StringNull(),
+		Timeouts:     nullTimeouts(),
+	}
+	resp := createResource(t, r, fake, &model)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected create diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(fake.Commands) != 1 || fake.Commands[0].Method != http.MethodPost || fake.Commands[0].Name != "group" {
+		t.Fatalf("Expected POST group to be run once, got %+v", fake.Commands)
+	}
+	var created restCommandResourceModel
+	resp.State.Get(ctx, &created)
+	if created.ID.ValueString() != "42" || created.StatusCode.ValueInt64() != http.StatusOK {
+		t.Errorf("Expected ID 42 and status 200, got %s and %s", created.ID, created.StatusCode)
+	}
+
+	// Without a delete command the resource is only removed from the state
+	deleteResp := &fwresource.DeleteResponse{State: resp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: resp.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() || len(fake.Commands) != 1 {
+		t.Errorf("Expected no command to be run on delete, got %v and %+v", deleteResp.Diagnostics, fake.Commands)
+	}
+}
+
+// testAccGroups serves the GuardAPI group command, keeping group descriptions by name
+type testAccGroups struct {
+	mu     sync.Mutex
+	groups map[string]string
+}
+
+func (g *testAccGroups) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var group struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil || group.Name == "" {
+		http.Error(w, `{"ErrorCode":400,"ErrorMessage":"name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+		g.groups[group.Name] = group.Description
+	case http.MethodDelete:
+		delete(g.groups, group.Name)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"ID":%d,"Message":"OK"}`, len(g.groups))
+}
+
+func (g *testAccGroups) description(name string) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	description, ok := g.groups[name]
+	return description, ok
+}
+
+func testAccRestCommandConfig(appliance *testAccAppliance, description string) string {
+	return appliance.config(`
+resource "guardium-data-protection_rest_command" "test" {
+  create = {
+    command = "group"
+    body    = jsonencode({ name = "servers", description = %[1]q })
+  }
+  update = {
+    command = "group"
+    body    = jsonencode({ name = "servers", description = %[1]q })
+  }
+  delete = {
+    command = "group"
+    body    = jsonencode({ name = "servers" })
+  }
+  ca_path = %[2]q
+}
+`, description, appliance.CAFile)
+}
+
+// testAccCheckGroupDescription checks the description of the servers group on the mock appliance
+func testAccCheckGroupDescription(groups *testAccGroups, expected string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if description, ok := groups.description("servers"); !ok || description != expected {
+			return fmt.Errorf("expected the servers group to be described as %q, got %q", expected, description)
+		}
+		return nil
+	}
+}
+
+func TestAccRestCommandResource(t *testing.T) {
+	appliance := newTestAccAppliance(t)
+	groups := &testAccGroups{groups: map[string]string{}}
+	appliance.HandleCommand("group", groups)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := groups.description("servers"); ok {
+				return fmt.Errorf("expected the servers group to be deleted")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRestCommandConfig(appliance, "Database servers"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("guardium-data-protection_rest_command.test", "id", "1"),
+					resource.TestCheckResourceAttr("guardium-data-protection_rest_command.test", "status_code", "200"),
+					resource.TestCheckResourceAttr("guardium-data-protection_rest_command.test", "result.Message", "OK"),
+					testAccCheckGroupDescription(groups, "Database servers"),
+				),
+			},
+			{
+				Config: testAccRestCommandConfig(appliance, "Production database servers"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("guardium-data-protection_rest_command.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("guardium-data-protection_rest_command.test", "id", "1"),
+					testAccCheckGroupDescription(groups, "Production database servers"),
+				),
+			},
+		},
+	})
+}
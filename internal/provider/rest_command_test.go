@@ -0,0 +1,295 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestJSONToDynamic(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected attr.Value
+	}{
+		{
+			name: "Object",
+			body: `{"name":"db2-prod","port":50000,"enabled":true,"owner":null}`,
+			expected: types.ObjectValueMust(
+				map[string]attr.Type{"name": types.StringType, "port": types.NumberType, "enabled": types.BoolType, "owner": types.StringType},
+				map[string]attr.Value{
+					"name":    types.StringValue("db2-prod"),
+					"port":    types.NumberValue(big.NewFloat(50000)),
+					"enabled": types.BoolValue(true),
+					"owner":   types.StringNull(),
+				},
+			),
+		},
+		{
+			name: "Top-level array of mixed types",
+			body: `[1,"two",{"three":3}]`,
+			expected: types.TupleValueMust(
+				[]attr.Type{types.NumberType, types.StringType, types.ObjectType{AttrTypes: map[string]attr.Type{"three": types.NumberType}}},
+				[]attr.Value{
+					types.NumberValue(big.NewFloat(1)),
+					types.StringValue("two"),
+					types.ObjectValueMust(map[string]attr.Type{"three": types.NumberType}, map[string]attr.Value{"three": types.NumberValue(big.NewFloat(3))}),
+				},
+			),
+		},
+		{
+			name:     "Empty array",
+			body:     `[]`,
+			expected: types.TupleValueMust([]attr.Type{}, []attr.Value{}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := jsonToDynamic([]byte(tc.body))
+			if got.IsNull() || !got.UnderlyingValue().Equal(tc.expected) {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+
+	for _, body := range []string{"Datasource registered", "", "<html></html>"} {
+		if got := jsonToDynamic([]byte(body)); !got.IsNull() {
+			t.Errorf("Expected a body that is not JSON to be null, got %s for %q", got, body)
+		}
+	}
+}
+
+func TestJSONToValueLargeNumber(t *testing.T) {
+	// IDs are kept exactly instead of being rounded to a float64
+	got := jsonToDynamic([]byte(`{"ID":20000000000000001}`)).UnderlyingValue().(types.Object).Attributes()["ID"].(types.Number)
+	if got.ValueBigFloat().Text('f', 0) != "20000000000000001" {
+		t.Errorf("Expected 20000000000000001, got %s", got.ValueBigFloat().Text('f', 0))
+	}
+}
+
+func TestResponseID(t *testing.T) {
+	testCases := map[string]string{
+		`{"ID":42,"Message":"Group created"}`: "42",
+		`{"ID":"group-1"}`:                    "group-1",
+		`{"ID":20000000000000001}`:            "20000000000000001",
+		`{"ID":0,"Message":"Done"}`:           "",
+		`{"Message":"Done"}`:                  "",
+		`[{"ID":1}]`:                          "",
+		`Group created`:                       "",
+		``:                                    "",
+	}
+
+	for body, expected := range testCases {
+		if got := responseID([]byte(body)); got != expected {
+			t.Errorf("Expected ID %q for %s, got %q", expected, body, got)
+		}
+	}
+}
+
+// testRestCommand returns a command model with the given values, empty strings being null
+func testRestCommand(method, command, body string, parameters map[string]string) restCommandModel {
+	m := restCommandModel{
+		Method:     types.StringNull(),
+		Command:    types.StringValue(command),
+		Parameters: types.MapNull(types.StringType),
+		Body:       types.StringNull(),
+	}
+	if method != "" {
+		m.Method = types.StringValue(method)
+	}
+	if body != "" {
+		m.Body = types.StringValue(body)
+	}
+	if parameters != nil {
+		m.Parameters, _ = types.MapValueFrom(context.Background(), types.StringType, parameters)
+	}
+	return m
+}
+
+func TestRestCommandModelValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		command       restCommandModel
+		defaultMethod string
+		expectedError string
+	}{
+		{
+			name:          "Parameters",
+			command:       testRestCommand("", "datasource", "", map[string]string{"type": "DB2"}),
+			defaultMethod: http.MethodGet,
+		},
+		{
+			name:          "Body",
+			command:       testRestCommand("put", "group", `{"desc":"servers"}`, nil),
+			defaultMethod: http.MethodPost,
+		},
+		{
+			name:          "Unsupported method",
+			command:       testRestCommand("OPTIONS", "group", "", nil),
+			defaultMethod: http.MethodPost,
+			expectedError: "Invalid method",
+		},
+		{
+			name:          "Empty command",
+			command:       testRestCommand("", " / ", "", nil),
+			defaultMethod: http.MethodPost,
+			expectedError: "Invalid command",
+		},
+		{
+			name:          "Parameters and body",
+			command:       testRestCommand("", "group", `{"desc":"servers"}`, map[string]string{"desc": "servers"}),
+			defaultMethod: http.MethodPost,
+			expectedError: "Conflicting command parameters",
+		},
+		{
+			name:          "Body that is not JSON",
+			command:       testRestCommand("", "group", "desc=servers", nil),
+			defaultMethod: http.MethodPost,
+			expectedError: "Invalid command body",
+		},
+		{
+			name:          "Body with GET",
+			command:       testRestCommand("", "group", `{"desc":"servers"}`, nil),
+			defaultMethod: http.MethodGet,
+			expectedError: "Invalid command body",
+		},
+		{
+			name: "Unknown values",
+			command: restCommandModel{
+				Method:     types.StringUnknown(),
+				Command:    types.StringUnknown(),
+				Parameters: types.MapNull(types.StringType),
+				Body:       types.StringUnknown(),
+			},
+			defaultMethod: http.MethodGet,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			tc.command.validate(path.Root("create"), tc.defaultMethod, &diags)
+			if tc.expectedError == "" {
+				if diags.HasError() {
+					t.Errorf("Expected no error but got: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || diags.Errors()[0].Summary() != tc.expectedError {
+				t.Errorf("Expected error %q, got %v", tc.expectedError, diags)
+			}
+		})
+	}
+}
+
+func TestRestCommandModelGDPCommand(t *testing.T) {
+	ctx := context.Background()
+
+	cmd, diags := testRestCommand("", "datasource", "", map[string]string{"type": "DB2"}).gdpCommand(ctx, http.MethodGet)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	if params, ok := cmd.Params.(map[string]string); cmd.Method != http.MethodGet || cmd.Name != "datasource" || !ok || params["type"] != "DB2" {
+		t.Errorf("Expected GET datasource with parameters, got %+v", cmd)
+	}
+
+	cmd, diags = testRestCommand("put", "group", `{"desc":"servers"}`, nil).gdpCommand(ctx, http.MethodPost)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	if body, ok := cmd.Params.(json.RawMessage); cmd.Method != http.MethodPut || !ok || string(body) != `{"desc":"servers"}` {
+		t.Errorf("Expected PUT group with the body as is, got %+v", cmd)
+	}
+
+	cmd, _ = testRestCommand("", "group", "", nil).gdpCommand(ctx, http.MethodDelete)
+	if cmd.Method != http.MethodDelete || cmd.Params != nil {
+		t.Errorf("Expected DELETE group without parameters, got %+v", cmd)
+	}
+}
+
+func TestRestCommandCreatePlanModifier(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &resource.SchemaResponse{}
+	NewRestCommandResource().Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	createAttribute := schemaResp.Schema.Attributes["create"].(schema.SingleNestedAttribute)
+	commandType := createAttribute.GetType().(types.ObjectType)
+
+	commandObject := func(body string) types.Object {
+		obj, diags := types.ObjectValueFrom(ctx, commandType.AttrTypes, testRestCommand("", "group", body, nil))
+		if diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+		return obj
+	}
+	oldCreate, newCreate := commandObject(`{"desc":"servers"}`), commandObject(`{"desc":"databases"}`)
+
+	testCases := []struct {
+		name            string
+		update          types.Object
+		expectedReplace bool
+	}{
+		{name: "Without update command", update: types.ObjectNull(commandType.AttrTypes), expectedReplace: true},
+		{name: "With update command", update: commandObject(`{"desc":"databases"}`), expectedReplace: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			model := restCommandResourceModel{
+				ID:           types.StringValue("42"),
+				Create:       newCreate,
+				Update:       tc.update,
+				Delete:       types.ObjectNull(commandType.AttrTypes),
+				AccessToken:  types.StringNull(),
+				CAPath:       types.StringNull(),
+				StatusCode:   types.Int64Value(http.StatusOK),
+				ResponseBody: types.StringValue(`{"ID":42}`),
+				Result:       types.DynamicNull(),
+				TargetUnit:   types.StringNull(),
+				Timeouts:     nullTimeouts(),
+			}
+			plan := tfsdk.Plan{Schema: schemaResp.Schema}
+			if diags := plan.Set(ctx, &model); diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+			model.Create = oldCreate
+			state := tfsdk.State{Schema: schemaResp.Schema}
+			if diags := state.Set(ctx, &model); diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+
+			req := planmodifier.ObjectRequest{
+				Path:        path.Root("create"),
+				Plan:        plan,
+				PlanValue:   newCreate,
+				State:       state,
+				StateValue:  oldCreate,
+				ConfigValue: newCreate,
+			}
+			resp := &planmodifier.ObjectResponse{PlanValue: newCreate}
+			for _, modifier := range createAttribute.PlanModifiers {
+				modifier.PlanModifyObject(ctx, req, resp)
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if resp.RequiresReplace != tc.expectedReplace {
+				t.Errorf("Expected requires replace %t, got %t", tc.expectedReplace, resp.RequiresReplace)
+			}
+		})
+	}
+}
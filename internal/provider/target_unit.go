@@ -0,0 +1,40 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+)
+
+// targetUnitDescription documents the `target_unit` attribute of resources and data sources
+const targetUnitDescription = "Managed unit, by name, host name or IP address, that the Central Manager runs the commands on. " +
+	"Overrides the provider `target_unit`, an empty string runs them on the Central Manager itself"
+
+// targetUnitAttribute is the `target_unit` attribute of resources routed to Central Manager managed units.
+// Applying the resource to another unit replaces it.
+func targetUnitAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: targetUnitDescription,
+		Optional:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// withTargetUnit routes the commands run with ctx to unit when it is set, leaving the provider `target_unit`
+// in effect otherwise
+func withTargetUnit(ctx context.Context, unit types.String) context.Context {
+	if unit.IsNull() || unit.IsUnknown() {
+		return ctx
+	}
+	return gdp.WithTargetUnit(ctx, unit.ValueString())
+}
@@ -0,0 +1,38 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// defaultOperationTimeout bounds resource operations without a `timeouts` setting
+const defaultOperationTimeout = 20 * time.Minute
+
+// timeoutsBlock is the `timeouts` block shared by every resource
+func timeoutsBlock(ctx context.Context) schema.Block {
+	return timeouts.Block(ctx, timeouts.Opts{
+		Create: true,
+		Read:   true,
+		Update: true,
+		Delete: true,
+	})
+}
+
+// withTimeout bounds ctx by the operation timeout configured in the resource `timeouts` block, so that a
+// hung call to the appliance fails instead of blocking the run. Errors are added to diags.
+func withTimeout(ctx context.Context, timeout func(context.Context, time.Duration) (time.Duration, diag.Diagnostics), diags *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	duration, d := timeout(ctx, defaultOperationTimeout)
+	diags.Append(d...)
+	if d.HasError() {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, duration)
+}
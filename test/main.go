@@ -0,0 +1,171 @@
+// Copyright (c) IBM Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Command main runs the mock Guardium Data Protection server, so that the provider and its examples can be
+// used without an appliance:
+//
+//	go run test/main.go [-addr localhost:8443] [-ca-file mock-ca.pem]
+//
+// The server listens over HTTPS with a self-signed certificate written to -ca-file, and prints the
+// GUARDIUM_* environment variables pointing the provider at it.
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp"
+	"github.ibm.com/Activity-Insights/terraform-provider-guardium-data-protection/internal/gdp/gdptest"
+)
+
+func main() {
+	server := gdptest.NewServer()
+
+	addr := flag.String("addr", "localhost:8443", "address to listen on")
+	caFile := flag.String("ca-file", "mock-ca.pem", "file the self-signed server certificate is written to")
+	managedUnits := flag.String("managed-units", "", "comma separated managed units, which makes the server a Central Manager")
+	flag.StringVar(&server.ClientID, "client-id", server.ClientID, "OAuth client ID")
+	flag.StringVar(&server.ClientSecret, "client-secret", server.ClientSecret, "OAuth client secret")
+	flag.StringVar(&server.Username, "username", server.Username, "user accepted by the password grant")
+	flag.StringVar(&server.Password, "password", server.Password, "password accepted by the password grant")
+	flag.StringVar(&server.Version, "version", server.Version, "Guardium version reported, empty for an appliance that does not report it")
+	flag.StringVar(&server.Patch, "patch", server.Patch, "Guardium patch level reported")
+	flag.StringVar(&server.UnitType, "unit-type", server.UnitType, "unit type reported, such as Aggregator, defaults to Manager with -managed-units and Collector otherwise")
+	flag.DurationVar(&server.TokenLifetime, "token-lifetime", server.TokenLifetime, "lifetime of issued access tokens")
+	flag.Parse()
+
+	for _, unit := range strings.Split(*managedUnits, ",") {
+		if unit = strings.TrimSpace(unit); unit != "" {
+			server.ManagedUnits = append(server.ManagedUnits, gdp.ManagedUnit{Name: unit, Host: unit, Online: true})
+		}
+	}
+
+	if err := run(server, *addr, *caFile); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(server *gdptest.Server, addr, caFile string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	cert, certPEM, err := selfSignedCertificate(host)
+	if err != nil {
+		return fmt.Errorf("error creating server certificate: %w", err)
+	}
+	if caFile, err = filepath.Abs(caFile); err != nil {
+		return err
+	}
+	if err := os.WriteFile(caFile, certPEM, 0o644); err != nil {
+		return fmt.Errorf("error writing server certificate: %w", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           logRequests(server),
+		TLSConfig:         &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if host == "" {
+		host = "localhost"
+	}
+	fmt.Printf("Mock Guardium Data Protection server listening on https://%s\n\n", net.JoinHostPort(host, port))
+	fmt.Println("Point the provider at it with:")
+	fmt.Printf("  export GUARDIUM_HOST=%s\n", host)
+	fmt.Printf("  export GUARDIUM_PORT=%s\n", port)
+	fmt.Printf("  export GUARDIUM_CA_FILE=%s\n", caFile)
+	fmt.Printf("  export GUARDIUM_CLIENT_ID=%s\n", server.ClientID)
+	fmt.Printf("  export GUARDIUM_CLIENT_SECRET=%s\n", server.ClientSecret)
+	fmt.Printf("  export GUARDIUM_USERNAME=%s\n", server.Username)
+	fmt.Printf("  export GUARDIUM_PASSWORD=%s\n\n", server.Password)
+
+	if err := httpServer.ListenAndServeTLS("", ""); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// selfSignedCertificate returns a certificate for host, localhost and the loopback addresses, and its PEM
+// encoding for clients to trust
+func selfSignedCertificate(host string) (tls.Certificate, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "Mock Guardium Data Protection"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(30 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = append(template.IPAddresses, ip)
+	} else if host != "" && host != "localhost" {
+		template.DNSNames = append(template.DNSNames, host)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// statusRecorder records the status of a response for logging
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func logRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(recorder, r)
+		log.Printf("%s %s %d", r.Method, r.URL.Path, recorder.status)
+	})
+}